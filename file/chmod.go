@@ -0,0 +1,76 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+)
+
+type chmodOptions struct {
+	dirMode *os.FileMode
+}
+
+// ChmodOption customizes Chmod.
+type ChmodOption func(*chmodOptions)
+
+// DirMode applies mode to directories instead of the mode passed to Chmod,
+// e.g. Chmod(fp, 0644, true, DirMode(0755)).
+func DirMode(mode os.FileMode) ChmodOption {
+	return func(o *chmodOptions) {
+		o.dirMode = &mode
+	}
+}
+
+// Chmod sets mode on fp. When recursive is true and fp is a directory,
+// every entry under it is changed as well. Symbolic links are skipped.
+func Chmod(fp string, mode os.FileMode, recursive bool, opts ...ChmodOption) error {
+	var o chmodOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	modeOf := func(info os.FileInfo) os.FileMode {
+		if info.IsDir() && o.dirMode != nil {
+			return *o.dirMode
+		}
+		return mode
+	}
+
+	if !recursive {
+		info, err := os.Stat(fp)
+		if err != nil {
+			return err
+		}
+		return os.Chmod(fp, modeOf(info))
+	}
+
+	// directories are changed after their entries, so a mode without the
+	// search bit does not lock the walk out of them
+	type dir struct {
+		path string
+		mode os.FileMode
+	}
+	var dirs []dir
+	err := filepath.Walk(fp, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		if info.IsDir() {
+			dirs = append(dirs, dir{p, modeOf(info)})
+			return nil
+		}
+		return os.Chmod(p, modeOf(info))
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Chmod(dirs[i].path, dirs[i].mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
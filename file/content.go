@@ -0,0 +1,29 @@
+package file
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+)
+
+// ContentPath returns the content-addressable path of data under base,
+// sharded by the first two byte pairs of its SHA-256,
+// e.g. base/ab/cd/abcdef0123....
+func ContentPath(base string, data []byte) string {
+	sum := sha256.Sum256(data)
+	return shardedPath(base, hex.EncodeToString(sum[:]))
+}
+
+// ContentPathFile is like ContentPath for the contents of the file src,
+// which is hashed without being loaded into memory.
+func ContentPathFile(base, src string) (string, error) {
+	sum, err := SHA256(src)
+	if err != nil {
+		return "", err
+	}
+	return shardedPath(base, sum), nil
+}
+
+func shardedPath(base, sum string) string {
+	return filepath.Join(base, sum[0:2], sum[2:4], sum)
+}
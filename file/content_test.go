@@ -0,0 +1,37 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestContentPath(t *testing.T) {
+	const sum = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	want := filepath.Join("/blobs", "2c", "f2", sum)
+
+	if got := ContentPath("/blobs", []byte("hello")); got != want {
+		t.Errorf("error, ContentPath = %s, want %s", got, want)
+	}
+	if ContentPath("/blobs", []byte("hello")) == ContentPath("/blobs", []byte("world")) {
+		t.Error("error, ContentPath collides for different content")
+	}
+
+	root, err := ioutil.TempDir("", "test_content_path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	src := filepath.Join(root, "hello.txt")
+	if _, err := WriteString(src, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ContentPathFile("/blobs", src); err != nil || got != want {
+		t.Errorf("error, ContentPathFile = %s, %v, want %s", got, err, want)
+	}
+	if _, err := ContentPathFile("/blobs", filepath.Join(root, "missing")); !os.IsNotExist(err) {
+		t.Error("error, ContentPathFile missing source", err)
+	}
+}
@@ -0,0 +1,88 @@
+package file
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+)
+
+// Copy copies the contents and mode bits of the file src to dst, creating
+// the destination directory if needed and overwriting dst if it exists.
+func Copy(src, dst string) error {
+	return copyFile(src, dst, nil)
+}
+
+// copyFile implements Copy, passing the destination through wrap if set.
+func copyFile(src, dst string, wrap func(io.Writer) io.Writer) (err error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("copy %s: is a directory", src)
+	}
+	// truncating dst would destroy src when both are the same file,
+	// including through a symlink or hard link
+	if dstInfo, err := os.Stat(dst); err == nil && os.SameFile(info, dstInfo) {
+		return fmt.Errorf("copy %s to %s: same file", src, dst)
+	}
+
+	if err := EnsureDirRW(Dir(dst)); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		cerr := out.Close()
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	var w io.Writer = out
+	if wrap != nil {
+		w = wrap(out)
+	}
+	if _, err = io.Copy(w, in); err != nil {
+		return err
+	}
+	// OpenFile leaves the mode of an existing dst untouched and applies umask.
+	return out.Chmod(info.Mode().Perm())
+}
+
+// MD5 returns the hex-encoded MD5 checksum of the file at fp.
+func MD5(fp string) (string, error) {
+	return checksum(fp, md5.New())
+}
+
+// SHA256 returns the hex-encoded SHA-256 checksum of the file at fp.
+func SHA256(fp string) (string, error) {
+	return checksum(fp, sha256.New())
+}
+
+// checksum streams the file at fp through h.
+func checksum(fp string, h hash.Hash) (string, error) {
+	f, err := os.Open(fp)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,157 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCopy(t *testing.T) {
+	root, err := ioutil.TempDir("", "test_copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	small := filepath.Join(root, "small.txt")
+	if err := ioutil.WriteFile(small, []byte("hello"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(root, "empty.txt")
+	if err := ioutil.WriteFile(empty, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, src := range []string{small, empty} {
+		dst := filepath.Join(root, "nested", "dir", filepath.Base(src))
+		if err := Copy(src, dst); err != nil {
+			t.Fatal("error, Copy", err)
+		}
+		// copying again overwrites the existing destination
+		if err := Copy(src, dst); err != nil {
+			t.Fatal("error, Copy overwrite", err)
+		}
+
+		want, _ := ioutil.ReadFile(src)
+		got, _ := ioutil.ReadFile(dst)
+		if string(got) != string(want) {
+			t.Errorf("error, Copy %s content %q, want %q", src, got, want)
+		}
+		srcInfo, _ := os.Stat(src)
+		dstInfo, _ := os.Stat(dst)
+		if srcInfo.Mode() != dstInfo.Mode() {
+			t.Errorf("error, Copy %s mode %v, want %v", src, dstInfo.Mode(), srcInfo.Mode())
+		}
+	}
+
+	if err := Copy(filepath.Join(root, "missing"), filepath.Join(root, "out")); !os.IsNotExist(err) {
+		t.Error("error, Copy missing source", err)
+	}
+	if err := Copy(root, filepath.Join(root, "out")); err == nil {
+		t.Error("error, Copy directory source")
+	}
+}
+
+func TestCopySameFile(t *testing.T) {
+	root, err := ioutil.TempDir("", "test_copy_same")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	src := filepath.Join(root, "precious.txt")
+	if err := ioutil.WriteFile(src, []byte("precious"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	symlink := filepath.Join(root, "symlink.txt")
+	if err := os.Symlink(src, symlink); err != nil {
+		t.Fatal(err)
+	}
+	hardlink := filepath.Join(root, "hardlink.txt")
+	if err := os.Link(src, hardlink); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dst := range []string{src, symlink, hardlink} {
+		if err := Copy(src, dst); err == nil {
+			t.Errorf("error, Copy onto %s succeeded", dst)
+		}
+		if got, _ := ioutil.ReadFile(src); string(got) != "precious" {
+			t.Fatalf("error, Copy onto %s left %q", dst, got)
+		}
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	root, err := ioutil.TempDir("", "test_checksum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	small := filepath.Join(root, "small.txt")
+	if err := ioutil.WriteFile(small, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(root, "empty.txt")
+	if err := ioutil.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		fp     string
+		md5    string
+		sha256 string
+	}{
+		{small, "5d41402abc4b2a76b9719d911017c592", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{empty, "d41d8cd98f00b204e9800998ecf8427e", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+	}
+	for _, tt := range tests {
+		if sum, err := MD5(tt.fp); err != nil || sum != tt.md5 {
+			t.Errorf("error, MD5 %s = %s, %v", tt.fp, sum, err)
+		}
+		if sum, err := SHA256(tt.fp); err != nil || sum != tt.sha256 {
+			t.Errorf("error, SHA256 %s = %s, %v", tt.fp, sum, err)
+		}
+	}
+
+	if _, err := MD5(filepath.Join(root, "missing")); !os.IsNotExist(err) {
+		t.Error("error, MD5 missing file", err)
+	}
+	if _, err := SHA256(filepath.Join(root, "missing")); !os.IsNotExist(err) {
+		t.Error("error, SHA256 missing file", err)
+	}
+}
+
+func TestCopyRateLimited(t *testing.T) {
+	root, err := ioutil.TempDir("", "test_copy_rate_limited")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	src := filepath.Join(root, "src.bin")
+	data := make([]byte, 200<<10)
+	if err := ioutil.WriteFile(src, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(root, "dst.bin")
+	start := time.Now()
+	if err := CopyRateLimited(src, dst, 400<<10); err != nil {
+		t.Fatal("error, CopyRateLimited", err)
+	}
+	// 200KB at 400KB/s
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond || elapsed > 1500*time.Millisecond {
+		t.Errorf("error, CopyRateLimited took %v, want about 500ms", elapsed)
+	}
+	if size, _ := FileSize(dst); size != int64(len(data)) {
+		t.Errorf("error, CopyRateLimited copied %d bytes", size)
+	}
+
+	if err := CopyRateLimited(src, dst, 0); err == nil {
+		t.Error("error, CopyRateLimited accepted a zero rate")
+	}
+}
@@ -0,0 +1,43 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+var (
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// ToUTF8 reads the file and returns its content as UTF-8. UTF-16 with a
+// byte order mark and GBK (decoded as its superset GB18030) are converted;
+// content that is already valid UTF-8 is returned unchanged.
+func ToUTF8(filePath string) ([]byte, error) {
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var enc encoding.Encoding
+	switch {
+	case bytes.HasPrefix(b, bomUTF16LE), bytes.HasPrefix(b, bomUTF16BE):
+		enc = unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM)
+	case utf8.Valid(b):
+		return b, nil
+	default:
+		enc = simplifiedchinese.GB18030
+	}
+
+	out, err := enc.NewDecoder().Bytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %s", filePath, err)
+	}
+	return out, nil
+}
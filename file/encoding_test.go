@@ -0,0 +1,54 @@
+package file
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestToUTF8(t *testing.T) {
+	root, err := ioutil.TempDir("", "test_to_utf8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	const want = "姓名,城市\n张三,上海\n"
+	fixtures := map[string]string{
+		"gbk.csv":      "d0d5c3fb2cb3c7cad00ad5c5c8fd2cc9cfbaa30a",
+		"utf16le.csv":  "fffe" + "d3590d542c00ce57025e0a00205f094e2c000a4e776d0a00",
+		"utf16be.csv":  "feff" + "59d3540d002c57ce5e02000a5f204e09002c4e0a6d77000a",
+		"utf8.csv":     hex.EncodeToString([]byte(want)),
+		"utf8-bom.csv": "efbbbf" + hex.EncodeToString([]byte(want)),
+	}
+
+	for name, content := range fixtures {
+		b, err := hex.DecodeString(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fp := filepath.Join(root, name)
+		if err := ioutil.WriteFile(fp, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := ToUTF8(fp)
+		if err != nil {
+			t.Errorf("error, ToUTF8 %s: %v", name, err)
+			continue
+		}
+		expect := want
+		if name == "utf8-bom.csv" {
+			expect = string(b)
+		}
+		if string(got) != expect {
+			t.Errorf("error, ToUTF8 %s = %q, want %q", name, got, expect)
+		}
+	}
+
+	if _, err := ToUTF8(filepath.Join(root, "missing.csv")); !os.IsNotExist(err) {
+		t.Error("error, ToUTF8 missing file", err)
+	}
+}
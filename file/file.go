@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
@@ -17,13 +18,22 @@ func SelfPath() string {
 	return pt
 }
 
-// get absolute filepath, based on built executable file
+// RealPath returns the absolute canonical path of fp with all symbolic
+// links resolved, so two paths naming the same file compare equal.
 func RealPath(fp string) (string, error) {
-	if path.IsAbs(fp) {
-		return fp, nil
+	abs, err := filepath.Abs(fp)
+	if err != nil {
+		return "", err
+	}
+
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", &os.PathError{Op: "realpath", Path: fp, Err: os.ErrNotExist}
+		}
+		return "", err
 	}
-	wd, err := os.Getwd()
-	return path.Join(wd, fp), err
+	return resolved, nil
 }
 
 // SelfDir gets compiled executable file directory
@@ -60,8 +70,7 @@ func EnsureDirRW(dataDir string) error {
 		return err
 	}
 
-	checkFile := fmt.Sprintf("%s/rw.%d", dataDir, time.Now().UnixNano())
-	fd, err := Create(checkFile)
+	fd, err := ioutil.TempFile(dataDir, fmt.Sprintf("rw.%d.", time.Now().UnixNano()))
 	if err != nil {
 		if os.IsPermission(err) {
 			return fmt.Errorf("open %s: rw permission denied", dataDir)
@@ -73,7 +82,7 @@ func EnsureDirRW(dataDir string) error {
 		return fmt.Errorf("close error: %s", err)
 	}
 
-	if err := Remove(checkFile); err != nil {
+	if err := Remove(fd.Name()); err != nil {
 		return fmt.Errorf("remove error: %s", err)
 	}
 
@@ -211,6 +220,37 @@ func FilesUnder(dirPath string) ([]string, error) {
 	return ret, nil
 }
 
+// ListByMtime returns the paths of the regular files directly under dir,
+// sorted by modification time, oldest first when ascending is true.
+// Files with the same mtime are ordered by name.
+func ListByMtime(dir string, ascending bool) ([]string, error) {
+	fs, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := fs[:0]
+	for _, f := range fs {
+		if f.Mode().IsRegular() {
+			files = append(files, f)
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		ti, tj := files[i].ModTime(), files[j].ModTime()
+		if ti.Equal(tj) {
+			return files[i].Name() < files[j].Name()
+		}
+		return ti.Before(tj) == ascending
+	})
+
+	ret := make([]string, len(files))
+	for i, f := range files {
+		ret[i] = filepath.Join(dir, f.Name())
+	}
+	return ret, nil
+}
+
 func MustOpenLogFile(fp string) *os.File {
 	if strings.Contains(fp, "/") {
 		dir := Dir(fp)
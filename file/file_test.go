@@ -1,8 +1,13 @@
 package file
 
 import (
+	"io/ioutil"
+	"os"
 	"os/user"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestEnsureDir(t *testing.T) {
@@ -40,3 +45,160 @@ func TestEnsureDirRW(t *testing.T) {
 		t.Error("error, EnsureDirRW", err1)
 	}
 }
+
+func TestFreeSpace(t *testing.T) {
+	free, err := FreeSpace(os.TempDir())
+	if err == ErrFreeSpaceUnsupported {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal("error, FreeSpace", err)
+	}
+	if free == 0 {
+		t.Error("error, FreeSpace returned 0 for temp dir")
+	}
+
+	if _, err := FreeSpace("/path/does/not/exist"); err == nil {
+		t.Error("error, FreeSpace on missing path")
+	}
+}
+
+func TestChmod(t *testing.T) {
+	root, err := ioutil.TempDir("", "test_chmod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	for _, fp := range []string{"a.txt", "sub/b.txt", "sub/deeper/c.txt"} {
+		if _, err := WriteString(filepath.Join(root, fp), fp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Chmod(filepath.Join(root, "a.txt"), 0600, false); err != nil {
+		t.Fatal("error, Chmod", err)
+	}
+	if info, _ := os.Stat(filepath.Join(root, "a.txt")); info.Mode().Perm() != 0600 {
+		t.Errorf("error, Chmod a.txt mode %v", info.Mode().Perm())
+	}
+
+	if err := Chmod(root, 0640, true, DirMode(0750)); err != nil {
+		t.Fatal("error, Chmod recursive", err)
+	}
+	expect := map[string]os.FileMode{
+		".":                0750,
+		"a.txt":            0640,
+		"sub":              0750,
+		"sub/b.txt":        0640,
+		"sub/deeper":       0750,
+		"sub/deeper/c.txt": 0640,
+	}
+	for fp, mode := range expect {
+		info, err := os.Stat(filepath.Join(root, fp))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != mode {
+			t.Errorf("error, Chmod %s mode %v, want %v", fp, info.Mode().Perm(), mode)
+		}
+	}
+
+	// without DirMode, directories lose their search bit only once the
+	// entries under them are done
+	if err := Chmod(root, 0644, true); err != nil {
+		t.Fatal("error, Chmod recursive without DirMode", err)
+	}
+	for _, fp := range []string{".", "a.txt", "sub", "sub/b.txt", "sub/deeper", "sub/deeper/c.txt"} {
+		p := filepath.Join(root, fp)
+		info, err := os.Lstat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0644 {
+			t.Errorf("error, Chmod %s mode %v, want 0644", fp, info.Mode().Perm())
+		}
+		// restore the search bit to reach the next level and clean up
+		if info.IsDir() {
+			os.Chmod(p, 0755)
+		}
+	}
+}
+
+func TestRealPath(t *testing.T) {
+	root, err := ioutil.TempDir("", "test_real_path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		t.Fatal(err)
+	}
+
+	target := filepath.Join(root, "target.txt")
+	if _, err := WriteString(target, "target"); err != nil {
+		t.Fatal(err)
+	}
+	first, second := filepath.Join(root, "first"), filepath.Join(root, "second")
+	if err := os.Symlink(target, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("first", second); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err := RealPath(second)
+	if err != nil {
+		t.Fatal("error, RealPath", err)
+	}
+	if resolved != target {
+		t.Errorf("error, RealPath resolved %s, want %s", resolved, target)
+	}
+
+	if _, err := RealPath(filepath.Join(root, "missing")); !os.IsNotExist(err) {
+		t.Error("error, RealPath on missing path", err)
+	}
+}
+
+func TestListByMtime(t *testing.T) {
+	root, err := ioutil.TempDir("", "test_list_by_mtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	now := time.Now()
+	ages := map[string]time.Duration{"new.log": time.Minute, "old.log": time.Hour, "mid.log": 10 * time.Minute}
+	for name, age := range ages {
+		fp := filepath.Join(root, name)
+		if _, err := WriteString(fp, name); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(fp, now, now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := EnsureDir(filepath.Join(root, "subdir")); err != nil {
+		t.Fatal(err)
+	}
+
+	join := func(names ...string) []string {
+		for i := range names {
+			names[i] = filepath.Join(root, names[i])
+		}
+		return names
+	}
+	for ascending, want := range map[bool][]string{
+		true:  join("old.log", "mid.log", "new.log"),
+		false: join("new.log", "mid.log", "old.log"),
+	} {
+		got, err := ListByMtime(root, ascending)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("error, ListByMtime ascending=%v = %v, %v, want %v", ascending, got, err, want)
+		}
+	}
+
+	if _, err := ListByMtime(filepath.Join(root, "missing"), true); !os.IsNotExist(err) {
+		t.Error("error, ListByMtime missing dir", err)
+	}
+}
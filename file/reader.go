@@ -2,7 +2,12 @@ package file
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -64,3 +69,66 @@ func ReadLine(r *bufio.Reader) ([]byte, error) {
 
 	return line, err
 }
+
+// ReadLineAt returns the nth line (1-based) of the file without its line
+// ending, scanning the file rather than loading it into memory.
+func ReadLineAt(filePath string, n int) (string, error) {
+	if n < 1 {
+		return "", fmt.Errorf("line %d out of range", n)
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for i := 1; ; i++ {
+		line, err := ReadLine(r)
+		if err == io.EOF {
+			return "", fmt.Errorf("line %d out of range, %s has %d lines", n, filePath, i-1)
+		}
+		if err != nil {
+			return "", err
+		}
+		if i == n {
+			return string(line), nil
+		}
+	}
+}
+
+// ReadNDJSON reads the newline-delimited JSON file at filePath and calls fn
+// with each object in turn, skipping blank lines. It stops at the first
+// line that is not valid JSON or the first error returned by fn, and
+// returns that error.
+func ReadNDJSON(filePath string, fn func(raw json.RawMessage) error) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for i := 1; ; i++ {
+		line, err := ReadLine(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			return fmt.Errorf("line %d of %s is not valid JSON", i, filePath)
+		}
+		// line aliases the reader's buffer, fn may keep what it is given
+		if err := fn(append(json.RawMessage(nil), line...)); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadLineAt(t *testing.T) {
+	root, err := ioutil.TempDir("", "test_read_line_at")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	fp := filepath.Join(root, "lines.txt")
+	if _, err := WriteString(fp, "first\r\nsecond\nthird"); err != nil {
+		t.Fatal(err)
+	}
+
+	for n, want := range map[int]string{1: "first", 2: "second", 3: "third"} {
+		got, err := ReadLineAt(fp, n)
+		if err != nil || got != want {
+			t.Errorf("error, ReadLineAt %d = %q, %v, want %q", n, got, err, want)
+		}
+	}
+
+	for _, n := range []int{0, 4} {
+		if _, err := ReadLineAt(fp, n); err == nil {
+			t.Errorf("error, ReadLineAt %d out of range", n)
+		}
+	}
+}
+
+func TestReadNDJSON(t *testing.T) {
+	root, err := ioutil.TempDir("", "test_read_ndjson")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	fp := filepath.Join(root, "events.ndjson")
+	content := `{"id":1,"name":"first"}` + "\n\n" +
+		`{"id":2,"name":"second"}` + "\r\n   \n" +
+		`{"id":3,"name":"third"}`
+	if _, err := WriteString(fp, content); err != nil {
+		t.Fatal(err)
+	}
+
+	type event struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	var events []event
+	err = ReadNDJSON(fp, func(raw json.RawMessage) error {
+		var e event
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return err
+		}
+		events = append(events, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatal("error, ReadNDJSON", err)
+	}
+	if len(events) != 3 || events[1] != (event{2, "second"}) {
+		t.Errorf("error, ReadNDJSON decoded %+v", events)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = ReadNDJSON(fp, func(json.RawMessage) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("error, ReadNDJSON callback error = %v after %d calls", err, calls)
+	}
+
+	bad := filepath.Join(root, "bad.ndjson")
+	if _, err := WriteString(bad, "{\"id\":1}\n{oops\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReadNDJSON(bad, func(json.RawMessage) error { return nil }); err == nil {
+		t.Error("error, ReadNDJSON accepted invalid JSON")
+	}
+}
@@ -0,0 +1,7 @@
+package file
+
+import "errors"
+
+// ErrFreeSpaceUnsupported is returned by FreeSpace on platforms where it
+// is not implemented.
+var ErrFreeSpaceUnsupported = errors.New("free space not supported on this platform")
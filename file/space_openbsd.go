@@ -0,0 +1,16 @@
+//go:build openbsd
+// +build openbsd
+
+package file
+
+import "syscall"
+
+// FreeSpace returns the number of bytes available to an unprivileged user
+// on the filesystem containing fp.
+func FreeSpace(fp string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(fp, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.F_bavail) * uint64(st.F_bsize), nil
+}
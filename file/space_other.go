@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd && !dragonfly && !openbsd && !windows
+// +build !linux,!darwin,!freebsd,!dragonfly,!openbsd,!windows
+
+package file
+
+// FreeSpace returns ErrFreeSpaceUnsupported: the syscall package has no
+// statfs for this platform.
+func FreeSpace(fp string) (uint64, error) {
+	return 0, ErrFreeSpaceUnsupported
+}
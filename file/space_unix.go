@@ -0,0 +1,16 @@
+//go:build linux || darwin || freebsd || dragonfly
+// +build linux darwin freebsd dragonfly
+
+package file
+
+import "syscall"
+
+// FreeSpace returns the number of bytes available to an unprivileged user
+// on the filesystem containing fp.
+func FreeSpace(fp string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(fp, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
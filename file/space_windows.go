@@ -0,0 +1,32 @@
+//go:build windows
+// +build windows
+
+package file
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var getDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// FreeSpace returns the number of bytes available to the calling user
+// on the volume containing fp.
+func FreeSpace(fp string) (uint64, error) {
+	p, err := syscall.UTF16PtrFromString(fp)
+	if err != nil {
+		return 0, err
+	}
+
+	var avail, total, free uint64
+	r, _, err := getDiskFreeSpaceEx.Call(
+		uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(&avail)),
+		uintptr(unsafe.Pointer(&total)),
+		uintptr(unsafe.Pointer(&free)),
+	)
+	if r == 0 {
+		return 0, err
+	}
+	return avail, nil
+}
@@ -0,0 +1,74 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// CopyRateLimited is like Copy but throttles writes to about bytesPerSec
+// using a token bucket, so large copies do not saturate disk IO.
+func CopyRateLimited(src, dst string, bytesPerSec int64) error {
+	if bytesPerSec <= 0 {
+		return fmt.Errorf("copy %s: invalid rate %d bytes/s", src, bytesPerSec)
+	}
+	return copyFile(src, dst, func(w io.Writer) io.Writer {
+		return newThrottledWriter(w, bytesPerSec)
+	})
+}
+
+// throttledWriter is a token bucket in front of w. The bucket holds at
+// most a tenth of a second worth of tokens and starts empty, so the overall
+// rate stays close to the limit even for short copies.
+type throttledWriter struct {
+	w      io.Writer
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+func newThrottledWriter(w io.Writer, bytesPerSec int64) *throttledWriter {
+	burst := int(bytesPerSec / 10)
+	if burst < 1 {
+		burst = 1
+	}
+	return &throttledWriter{w: w, rate: float64(bytesPerSec), burst: burst, last: time.Now()}
+}
+
+func (t *throttledWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > t.burst {
+			chunk = chunk[:t.burst]
+		}
+		t.wait(len(chunk))
+
+		n, err := t.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// wait blocks until n tokens are available and takes them.
+func (t *throttledWriter) wait(n int) {
+	now := time.Now()
+	t.tokens += now.Sub(t.last).Seconds() * t.rate
+	if t.tokens > float64(t.burst) {
+		t.tokens = float64(t.burst)
+	}
+	t.last = now
+
+	if missing := float64(n) - t.tokens; missing > 0 {
+		time.Sleep(time.Duration(missing / t.rate * float64(time.Second)))
+		t.tokens = 0
+		t.last = time.Now()
+		return
+	}
+	t.tokens -= float64(n)
+}
@@ -1,9 +1,14 @@
 package file
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path"
+
+	"gopkg.in/yaml.v2"
 )
 
 func WriteBytes(filePath string, b []byte) (n int, err error) {
@@ -30,3 +35,74 @@ func WriteBytes(filePath string, b []byte) (n int, err error) {
 func WriteString(filePath string, s string) (int, error) {
 	return WriteBytes(filePath, []byte(s))
 }
+
+// WriteAtomic writes data to filePath so that readers see either the old
+// content or the complete new content, never a partial write. The data is
+// written and synced to a temp file in the same directory, which is then
+// renamed over filePath.
+func WriteAtomic(filePath string, data []byte, perm os.FileMode) error {
+	return WriteAtomicFunc(filePath, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// WriteAtomicFunc is like WriteAtomic, but the content is whatever write
+// writes to w, so it can be streamed rather than held in memory. If write
+// returns an error, filePath is left untouched.
+func WriteAtomicFunc(filePath string, perm os.FileMode, write func(w io.Writer) error) (err error) {
+	dir := path.Dir(filePath)
+	if err := EnsureDirRW(dir); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+path.Base(filePath)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err = write(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
+
+// WriteJSON marshals v as JSON, indented with two spaces when indent is
+// true, and writes it to filePath atomically.
+func WriteJSON(filePath string, v interface{}, indent bool) error {
+	var b []byte
+	var err error
+	if indent {
+		b, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		b, err = json.Marshal(v)
+	}
+	if err != nil {
+		return err
+	}
+	return WriteAtomic(filePath, append(b, '\n'), 0644)
+}
+
+// WriteYAML marshals v as YAML and writes it to filePath atomically.
+func WriteYAML(filePath string, v interface{}) error {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return WriteAtomic(filePath, b, 0644)
+}
@@ -0,0 +1,148 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestWriteAtomic(t *testing.T) {
+	root, err := ioutil.TempDir("", "test_write_atomic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	fp := filepath.Join(root, "conf", "state.json")
+	versions := make([][]byte, 8)
+	for i := range versions {
+		versions[i] = bytes.Repeat([]byte{byte('a' + i)}, 1<<16)
+	}
+
+	var wg sync.WaitGroup
+	for _, data := range versions {
+		wg.Add(1)
+		go func(data []byte) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if err := WriteAtomic(fp, data, 0640); err != nil {
+					t.Error("error, WriteAtomic", err)
+				}
+			}
+		}(data)
+	}
+	wg.Wait()
+
+	got, err := ioutil.ReadFile(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	complete := false
+	for _, data := range versions {
+		complete = complete || bytes.Equal(got, data)
+	}
+	if !complete {
+		t.Errorf("error, WriteAtomic left a partial file of %d bytes", len(got))
+	}
+
+	if info, _ := os.Stat(fp); info.Mode().Perm() != 0640 {
+		t.Errorf("error, WriteAtomic mode %v", info.Mode().Perm())
+	}
+	if names, _ := FilesUnder(filepath.Dir(fp)); len(names) != 1 {
+		t.Errorf("error, WriteAtomic left temp files %v", names)
+	}
+}
+
+func TestWriteAtomicFunc(t *testing.T) {
+	root, err := ioutil.TempDir("", "test_write_atomic_func")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	fp := filepath.Join(root, "out.txt")
+	if err := WriteAtomicFunc(fp, 0644, func(w io.Writer) error {
+		_, err := io.WriteString(w, "old")
+		return err
+	}); err != nil {
+		t.Fatal("error, WriteAtomicFunc", err)
+	}
+
+	failed := errors.New("failed")
+	err = WriteAtomicFunc(fp, 0644, func(w io.Writer) error {
+		io.WriteString(w, "partial")
+		return failed
+	})
+	if err != failed {
+		t.Error("error, WriteAtomicFunc returned", err)
+	}
+	if got, _ := ToString(fp); got != "old" {
+		t.Errorf("error, WriteAtomicFunc replaced content with %q", got)
+	}
+	if names, _ := FilesUnder(root); len(names) != 1 {
+		t.Errorf("error, WriteAtomicFunc left temp files %v", names)
+	}
+}
+
+type service struct {
+	Name  string   `json:"name" yaml:"name"`
+	Port  int      `json:"port" yaml:"port"`
+	Hosts []string `json:"hosts" yaml:"hosts"`
+}
+
+func TestWriteJSONAndYAML(t *testing.T) {
+	root, err := ioutil.TempDir("", "test_write_json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	want := service{Name: "api", Port: 8080, Hosts: []string{"a.example.com", "b.example.com"}}
+
+	for _, indent := range []bool{false, true} {
+		fp := filepath.Join(root, "json", "service.json")
+		if err := WriteJSON(fp, want, indent); err != nil {
+			t.Fatal("error, WriteJSON", err)
+		}
+		b, _ := ioutil.ReadFile(fp)
+		var got service
+		if err := json.Unmarshal(b, &got); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("error, WriteJSON round trip = %+v, %v", got, err)
+		}
+		if indented := bytes.Contains(b, []byte("\n  \"name\"")); indented != indent {
+			t.Errorf("error, WriteJSON indent=%v wrote %s", indent, b)
+		}
+	}
+
+	fp := filepath.Join(root, "yaml", "service.yaml")
+	if err := WriteYAML(fp, want); err != nil {
+		t.Fatal("error, WriteYAML", err)
+	}
+	b, _ := ioutil.ReadFile(fp)
+	var got service
+	if err := yaml.Unmarshal(b, &got); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("error, WriteYAML round trip = %+v, %v", got, err)
+	}
+
+	for _, dir := range []string{"json", "yaml"} {
+		if names, _ := FilesUnder(filepath.Join(root, dir)); len(names) != 1 {
+			t.Errorf("error, left temp files in %s: %v", dir, names)
+		}
+	}
+
+	if err := WriteJSON(filepath.Join(root, "bad.json"), make(chan int), false); err == nil {
+		t.Error("error, WriteJSON accepted an unmarshalable value")
+	}
+	if IsExist(filepath.Join(root, "bad.json")) {
+		t.Error("error, WriteJSON created a file for an unmarshalable value")
+	}
+}
@@ -0,0 +1,152 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BatchHook is a logrus hook that accumulates JSON-encoded entries and
+// ships them gzip-compressed, one entry per line, whenever batchSize
+// entries are pending or flushInterval elapses.
+//
+// Shipping happens on a background goroutine. Entries are dropped rather
+// than blocking the caller when the backlog is full, and failed batches
+// are discarded; Dropped and Failed report how many.
+type BatchHook struct {
+	ship      func([]byte) error
+	batchSize int
+	formatter logrus.Formatter
+
+	mu      sync.RWMutex
+	closed  bool
+	entries chan []byte
+	done    chan struct{}
+
+	dropped uint64
+	failed  uint64
+}
+
+// NewBatchHook starts a BatchHook. Call Close to flush pending entries
+// and stop it.
+func NewBatchHook(ship func([]byte) error, batchSize int, flushInterval time.Duration) *BatchHook {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	h := &BatchHook{
+		ship:      ship,
+		batchSize: batchSize,
+		formatter: &logrus.JSONFormatter{},
+		entries:   make(chan []byte, batchSize*4),
+		done:      make(chan struct{}),
+	}
+	go h.run(flushInterval)
+	return h
+}
+
+// Levels implements logrus.Hook.
+func (h *BatchHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.
+func (h *BatchHook) Fire(entry *logrus.Entry) error {
+	line, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.closed {
+		atomic.AddUint64(&h.dropped, 1)
+		return nil
+	}
+
+	select {
+	case h.entries <- line:
+	default:
+		atomic.AddUint64(&h.dropped, 1)
+	}
+	return nil
+}
+
+// Close ships any pending entries and stops the hook.
+func (h *BatchHook) Close() error {
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.entries)
+	}
+	h.mu.Unlock()
+
+	<-h.done
+	return nil
+}
+
+// Dropped returns the number of entries discarded because the backlog
+// was full or the hook was closed.
+func (h *BatchHook) Dropped() uint64 {
+	return atomic.LoadUint64(&h.dropped)
+}
+
+// Failed returns the number of batches ship returned an error for.
+func (h *BatchHook) Failed() uint64 {
+	return atomic.LoadUint64(&h.failed)
+}
+
+func (h *BatchHook) run(flushInterval time.Duration) {
+	defer close(h.done)
+
+	var tick <-chan time.Time
+	if flushInterval > 0 {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var batch bytes.Buffer
+	pending := 0
+	flush := func() {
+		if pending == 0 {
+			return
+		}
+		if err := h.send(batch.Bytes()); err != nil {
+			atomic.AddUint64(&h.failed, 1)
+		}
+		batch.Reset()
+		pending = 0
+	}
+
+	for {
+		select {
+		case line, ok := <-h.entries:
+			if !ok {
+				flush()
+				return
+			}
+			batch.Write(line)
+			if pending++; pending >= h.batchSize {
+				flush()
+			}
+		case <-tick:
+			flush()
+		}
+	}
+}
+
+func (h *BatchHook) send(lines []byte) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(lines); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return h.ship(buf.Bytes())
+}
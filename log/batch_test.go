@@ -0,0 +1,88 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type shipper struct {
+	mu      sync.Mutex
+	batches []string
+	fail    bool
+}
+
+func (s *shipper) ship(b []byte) error {
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	lines, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, string(lines))
+	if s.fail {
+		return errors.New("collector unavailable")
+	}
+	return nil
+}
+
+func (s *shipper) shipped() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.batches...)
+}
+
+func TestBatchHook(t *testing.T) {
+	s := &shipper{}
+	hook := NewBatchHook(s.ship, 3, time.Hour)
+
+	l := logrus.New()
+	l.Out = ioutil.Discard
+	l.AddHook(hook)
+	for i := 0; i < 7; i++ {
+		l.WithField("i", i).Info("batched")
+	}
+
+	assert.Eventually(t, func() bool { return len(s.shipped()) == 2 }, time.Second, 5*time.Millisecond)
+	for _, batch := range s.shipped() {
+		assert.Equal(t, 3, strings.Count(batch, "\n"))
+		assert.Contains(t, batch, `"msg":"batched"`)
+	}
+
+	assert.NoError(t, hook.Close())
+	batches := s.shipped()
+	if assert.Len(t, batches, 3) {
+		assert.Equal(t, 1, strings.Count(batches[2], "\n"))
+		assert.Contains(t, batches[2], `"i":6`)
+	}
+
+	l.Info("after close")
+	assert.Equal(t, uint64(1), hook.Dropped())
+}
+
+func TestBatchHookInterval(t *testing.T) {
+	s := &shipper{fail: true}
+	hook := NewBatchHook(s.ship, 100, 10*time.Millisecond)
+	defer hook.Close()
+
+	l := logrus.New()
+	l.Out = ioutil.Discard
+	l.AddHook(hook)
+	l.Info("flushed by interval")
+
+	assert.Eventually(t, func() bool { return hook.Failed() == 1 }, time.Second, 5*time.Millisecond)
+	assert.Len(t, s.shipped(), 1)
+}
@@ -0,0 +1,97 @@
+package log
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+type contextKey int
+
+const correlationIDKey contextKey = iota
+
+// CorrelationIDField is the field WithContext uses for the correlation ID.
+const CorrelationIDField = "correlation_id"
+
+type contextField struct {
+	key   interface{}
+	field string
+}
+
+var (
+	contextMu     sync.RWMutex
+	contextFields = []contextField{{correlationIDKey, CorrelationIDField}}
+	baggageKeys   []string
+)
+
+// RegisterContextKey makes WithContext copy the value stored in a context
+// under ctxKey into the field named fieldName. Registering a key again
+// replaces its field name.
+func RegisterContextKey(ctxKey interface{}, fieldName string) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+
+	for i := range contextFields {
+		if contextFields[i].key == ctxKey {
+			contextFields[i].field = fieldName
+			return
+		}
+	}
+	contextFields = append(contextFields, contextField{ctxKey, fieldName})
+}
+
+// EnableBaggageFields makes WithContext copy the named OpenTelemetry
+// baggage members of a context into fields of the same name. Members
+// missing from the baggage are skipped. Each call replaces the keys of the
+// previous one; calling it without keys disables baggage fields.
+func EnableBaggageFields(keys ...string) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	baggageKeys = append([]string(nil), keys...)
+}
+
+// ContextWithCorrelationID returns a copy of ctx carrying id.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, id)
+}
+
+// CorrelationID returns the correlation ID carried by ctx, if any.
+func CorrelationID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey).(string)
+	return id, ok && id != ""
+}
+
+// WithContext attaches the values carried by ctx under registered keys,
+// including the correlation ID, and the enabled baggage members to
+// a logger. Keys missing from ctx are skipped.
+func (l logger) WithContext(ctx context.Context) Logger {
+	contextMu.RLock()
+	defer contextMu.RUnlock()
+
+	fields := make(logrus.Fields)
+	for _, cf := range contextFields {
+		v := ctx.Value(cf.key)
+		if v == nil || v == "" {
+			continue
+		}
+		fields[cf.field] = v
+	}
+	if len(baggageKeys) > 0 {
+		bag := baggage.FromContext(ctx)
+		for _, key := range baggageKeys {
+			if v := bag.Member(key).Value(); v != "" {
+				fields[key] = v
+			}
+		}
+	}
+	if len(fields) > 0 {
+		l.entry = l.entry.WithFields(fields)
+	}
+	return l
+}
+
+func WithContext(ctx context.Context) Logger {
+	return baseLogger.WithContext(ctx)
+}
@@ -0,0 +1,59 @@
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+type traceKey struct{}
+
+func TestWithContext(t *testing.T) {
+	RegisterContextKey(traceKey{}, "trace_id")
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "4bf92f3577b34da6")
+	entry := capture(t, func() {
+		WithContext(ctx).With("user", "alice").Info("traced")
+	})
+	assert.Equal(t, "4bf92f3577b34da6", entry["trace_id"])
+	assert.Equal(t, "alice", entry["user"])
+
+	entry = capture(t, func() {
+		New().WithContext(context.Background()).Info("untraced")
+	})
+	assert.NotContains(t, entry, "trace_id")
+	assert.NotContains(t, entry, CorrelationIDField)
+
+	RegisterContextKey(traceKey{}, "trace")
+	defer RegisterContextKey(traceKey{}, "trace_id")
+	entry = capture(t, func() {
+		New().WithContext(ctx).Info("renamed")
+	})
+	assert.Equal(t, "4bf92f3577b34da6", entry["trace"])
+	assert.NotContains(t, entry, "trace_id")
+}
+
+func TestEnableBaggageFields(t *testing.T) {
+	EnableBaggageFields("tenant", "region")
+	defer EnableBaggageFields()
+
+	tenant, _ := baggage.NewMember("tenant", "acme")
+	plan, _ := baggage.NewMember("plan", "gold")
+	bag, _ := baggage.New(tenant, plan)
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+
+	entry := capture(t, func() {
+		WithContext(ctx).Info("with baggage")
+	})
+	assert.Equal(t, "acme", entry["tenant"])
+	assert.NotContains(t, entry, "region")
+	assert.NotContains(t, entry, "plan")
+
+	EnableBaggageFields()
+	entry = capture(t, func() {
+		WithContext(ctx).Info("disabled")
+	})
+	assert.NotContains(t, entry, "tenant")
+}
@@ -0,0 +1,23 @@
+package log
+
+import "sync/atomic"
+
+// EnvironmentField is the field that carries the deployment environment.
+const EnvironmentField = "env"
+
+// environment holds the string set by SetEnvironment.
+var environment atomic.Value
+
+// SetEnvironment sets the deployment environment, such as "prod",
+// "staging" or "dev", added to every entry of every logger under
+// EnvironmentField. It may be changed at any time; an empty env stops
+// adding the field.
+func SetEnvironment(env string) {
+	environment.Store(env)
+}
+
+// Environment returns the deployment environment set by SetEnvironment.
+func Environment() string {
+	env, _ := environment.Load().(string)
+	return env
+}
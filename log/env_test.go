@@ -0,0 +1,24 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetEnvironment(t *testing.T) {
+	defer SetEnvironment("")
+
+	entry := capture(t, func() { Info("no env") })
+	assert.NotContains(t, entry, EnvironmentField)
+
+	SetEnvironment("staging")
+	assert.Equal(t, "staging", Environment())
+	entry = capture(t, func() { New().Info("staging") })
+	assert.Equal(t, "staging", entry[EnvironmentField])
+
+	SetEnvironment("prod")
+	entry = capture(t, func() { With("k", "v").Info("prod") })
+	assert.Equal(t, "prod", entry[EnvironmentField])
+	assert.Equal(t, "v", entry["k"])
+}
@@ -0,0 +1,45 @@
+package log
+
+// FieldsError is an error carrying structured context that WithError
+// attaches to the entry.
+type FieldsError interface {
+	error
+	Fields() map[string]interface{}
+}
+
+// errorFields merges the fields of every FieldsError in the tree of err,
+// following both Unwrap() error and Unwrap() []error (errors.Join, or
+// fmt.Errorf with several %w) depth-first, like errors.Is. Fields of outer
+// errors, then of earlier branches, take precedence.
+func errorFields(err error) map[string]interface{} {
+	var fields map[string]interface{}
+	var walk func(err error)
+	walk = func(err error) {
+		for err != nil {
+			if fe, ok := err.(FieldsError); ok {
+				for k, v := range fe.Fields() {
+					if fields == nil {
+						fields = make(map[string]interface{})
+					}
+					if _, ok := fields[k]; !ok {
+						fields[k] = v
+					}
+				}
+			}
+
+			switch u := err.(type) {
+			case interface{ Unwrap() error }:
+				err = u.Unwrap()
+			case interface{ Unwrap() []error }:
+				for _, e := range u.Unwrap() {
+					walk(e)
+				}
+				return
+			default:
+				return
+			}
+		}
+	}
+	walk(err)
+	return fields
+}
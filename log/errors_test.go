@@ -0,0 +1,62 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type queryError struct {
+	err    error
+	fields map[string]interface{}
+}
+
+func (e *queryError) Error() string                  { return e.err.Error() }
+func (e *queryError) Unwrap() error                  { return e.err }
+func (e *queryError) Fields() map[string]interface{} { return e.fields }
+
+// joinError wraps a list of errors like errors.Join, which needs Go 1.20.
+type joinError []error
+
+func (e joinError) Error() string   { return fmt.Sprint([]error(e)) }
+func (e joinError) Unwrap() []error { return e }
+
+func TestWithErrorFields(t *testing.T) {
+	inner := &queryError{
+		err:    errors.New("connection reset"),
+		fields: map[string]interface{}{"table": "orders", "attempt": 1},
+	}
+	outer := &queryError{
+		err:    fmt.Errorf("load order: %w", inner),
+		fields: map[string]interface{}{"attempt": 3, "order_id": 42},
+	}
+
+	entry := capture(t, func() {
+		WithError(outer).Error("query failed")
+	})
+	assert.Equal(t, "load order: connection reset", entry["error"])
+	assert.Equal(t, "orders", entry["table"])
+	assert.Equal(t, float64(3), entry["attempt"])
+	assert.Equal(t, float64(42), entry["order_id"])
+
+	// errors.Join and fmt.Errorf with several %w wrap a list of errors
+	other := &queryError{
+		err:    errors.New("timeout"),
+		fields: map[string]interface{}{"table": "customers", "host": "db2"},
+	}
+	entry = capture(t, func() {
+		WithError(fmt.Errorf("sync: %w", joinError{outer, other})).Error("query failed")
+	})
+	assert.Equal(t, "orders", entry["table"])
+	assert.Equal(t, float64(3), entry["attempt"])
+	assert.Equal(t, float64(42), entry["order_id"])
+	assert.Equal(t, "db2", entry["host"])
+
+	entry = capture(t, func() {
+		New().WithError(errors.New("plain")).Error("query failed")
+	})
+	assert.Equal(t, "plain", entry["error"])
+	assert.NotContains(t, entry, "table")
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+type levelPayload struct {
+	Level  string   `json:"level"`
+	Levels []string `json:"levels,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// LevelHandler returns an http.Handler that reports the level of the base
+// logger on GET and changes it on PUT or POST.
+//
+// The new level is read from a JSON body ({"level":"debug"}), a plain text
+// body, or the "level" query parameter, using logrus.ParseLevel semantics.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			level, err := logrus.ParseLevel(requestedLevel(r))
+			if err != nil {
+				writeLevel(w, http.StatusBadRequest, levelPayload{
+					Level:  logrus.Level(GetLevel()).String(),
+					Levels: validLevels(),
+					Error:  err.Error(),
+				})
+				return
+			}
+			SetLevel(Level(level))
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		writeLevel(w, http.StatusOK, levelPayload{Level: logrus.Level(GetLevel()).String()})
+	})
+}
+
+func requestedLevel(r *http.Request) string {
+	if level := r.URL.Query().Get("level"); level != "" {
+		return level
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1024))
+	if err != nil {
+		return ""
+	}
+
+	var payload levelPayload
+	if json.Unmarshal(body, &payload) == nil {
+		return payload.Level
+	}
+	return strings.TrimSpace(string(body))
+}
+
+func validLevels() []string {
+	levels := make([]string, 0, len(logrus.AllLevels))
+	for _, level := range logrus.AllLevels {
+		levels = append(levels, level.String())
+	}
+	return levels
+}
+
+func writeLevel(w http.ResponseWriter, status int, payload levelPayload) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,84 @@
+package log
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelHandler(t *testing.T) {
+	defer SetLevel(GetLevel())
+	SetLevel(InfoLevel)
+
+	do := func(method, body string) (*httptest.ResponseRecorder, levelPayload) {
+		rec := httptest.NewRecorder()
+		LevelHandler().ServeHTTP(rec, httptest.NewRequest(method, "/debug/loglevel", strings.NewReader(body)))
+
+		var payload levelPayload
+		json.Unmarshal(rec.Body.Bytes(), &payload)
+		return rec, payload
+	}
+
+	rec, payload := do(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "info", payload.Level)
+
+	rec, payload = do(http.MethodPut, "debug")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "debug", payload.Level)
+	assert.Equal(t, DebugLevel, GetLevel())
+
+	rec, payload = do(http.MethodPost, `{"level":"warn"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "warning", payload.Level)
+	assert.Equal(t, WarnLevel, GetLevel())
+
+	rec, payload = do(http.MethodPut, "loud")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, payload.Levels, "debug")
+	assert.Equal(t, WarnLevel, GetLevel())
+
+	rec, _ = do(http.MethodDelete, "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+// run with -race: the handler changes the level while others are logging
+func TestLevelHandlerConcurrent(t *testing.T) {
+	defer SetLevel(GetLevel())
+	defer SetOut(os.Stderr)
+	SetOut(ioutil.Discard)
+
+	srv := httptest.NewServer(LevelHandler())
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+	for i, level := range []string{"debug", "warn", "info", "error", "trace", "info"} {
+		wg.Add(2)
+		go func(level string) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				req, _ := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader(level))
+				resp, err := http.DefaultClient.Do(req)
+				if assert.NoError(t, err) {
+					assert.Equal(t, http.StatusOK, resp.StatusCode)
+					resp.Body.Close()
+				}
+			}
+		}(level)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				Info("concurrent", i)
+				New().Debug("concurrent", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AddHook registers a logrus hook on the logger shared by the base logger
+// and every logger returned by New, so it fires for package-level calls
+// such as Error as well as for Logger.Error.
+func AddHook(hook logrus.Hook) {
+	origLogger.AddHook(hook)
+}
+
+// LevelCountHook is a hook that counts the entries logged at each level,
+// e.g. to export them as a metric.
+type LevelCountHook struct {
+	counts [TraceLevel + 1]uint64
+}
+
+// NewLevelCountHook returns a LevelCountHook with all counts at zero.
+func NewLevelCountHook() *LevelCountHook {
+	return &LevelCountHook{}
+}
+
+// Levels implements logrus.Hook.
+func (h *LevelCountHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.
+func (h *LevelCountHook) Fire(entry *logrus.Entry) error {
+	if int(entry.Level) < len(h.counts) {
+		atomic.AddUint64(&h.counts[entry.Level], 1)
+	}
+	return nil
+}
+
+// Count returns the number of entries logged at level so far.
+func (h *LevelCountHook) Count(level Level) uint64 {
+	if int(level) >= len(h.counts) {
+		return 0
+	}
+	return atomic.LoadUint64(&h.counts[level])
+}
@@ -0,0 +1,30 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddHook(t *testing.T) {
+	defer origLogger.ReplaceHooks(make(logrus.LevelHooks))
+	defer SetOut(os.Stderr)
+	SetOut(ioutil.Discard)
+
+	hook := NewLevelCountHook()
+	AddHook(hook)
+
+	Error("package level")
+	New().Error("logger")
+	New().With("k", "v").Errorf("logger %s", "with field")
+	Warn("warning")
+	Debug("below level")
+
+	assert.Equal(t, uint64(3), hook.Count(ErrorLevel))
+	assert.Equal(t, uint64(1), hook.Count(WarnLevel))
+	assert.Equal(t, uint64(0), hook.Count(DebugLevel))
+	assert.Equal(t, uint64(0), hook.Count(Level(42)))
+}
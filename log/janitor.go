@@ -0,0 +1,72 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/srelab/common/file"
+)
+
+// rotatedName matches the timestamp lumberjack inserts into the names of
+// the backups it rotates, e.g. app-2006-01-02T15-04-05.000.log.gz.
+var rotatedName = regexp.MustCompile(`-\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}\.\d{3}`)
+
+// StartLogJanitor keeps at most maxFiles rotated log files in dir,
+// removing the oldest ones by modification time, across all the writers
+// sharing the directory. Files currently being written to are never
+// removed. It prunes once before returning and then every interval, until
+// stop is called; with an interval of 0 or less it only prunes once.
+// Like lumberjack's MaxBackups, a maxFiles of 0 or less keeps every file,
+// so nothing is pruned.
+func StartLogJanitor(dir string, maxFiles int, interval time.Duration) (stop func()) {
+	if maxFiles <= 0 {
+		return func() {}
+	}
+	pruneRotated(dir, maxFiles)
+	if interval <= 0 {
+		return func() {}
+	}
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				pruneRotated(dir, maxFiles)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// pruneRotated removes the rotated log files in dir beyond the newest max.
+func pruneRotated(dir string, max int) {
+	files, err := file.ListByMtime(dir, false)
+	if err != nil {
+		Warnf("log janitor: %s", err)
+		return
+	}
+
+	kept := 0
+	for _, f := range files {
+		if !rotatedName.MatchString(filepath.Base(f)) {
+			continue
+		}
+		if kept < max {
+			kept++
+			continue
+		}
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			Warnf("log janitor: %s", err)
+		}
+	}
+}
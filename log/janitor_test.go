@@ -0,0 +1,79 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartLogJanitor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test_log_janitor")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	touch := func(name string, age time.Duration) {
+		fp := filepath.Join(dir, name)
+		assert.NoError(t, ioutil.WriteFile(fp, []byte(name), 0644))
+		assert.NoError(t, os.Chtimes(fp, now, now.Add(-age)))
+	}
+	names := func() []string {
+		fs, _ := ioutil.ReadDir(dir)
+		var ret []string
+		for _, f := range fs {
+			ret = append(ret, f.Name())
+		}
+		return ret
+	}
+
+	touch("app.log", 0)
+	touch("access.log", 0)
+	touch("app-2020-01-01T00-00-00.000.log.gz", 5*time.Hour)
+	touch("access-2020-01-01T01-00-00.000.log.gz", 4*time.Hour)
+	touch("app-2020-01-01T02-00-00.000.log.gz", 3*time.Hour)
+	touch("access-2020-01-01T03-00-00.000.log", 2*time.Hour)
+
+	// nothing is pruned without a positive cap
+	StartLogJanitor(dir, 0, time.Millisecond)()
+	assert.Len(t, names(), 6)
+
+	stop := StartLogJanitor(dir, 2, 10*time.Millisecond)
+	defer stop()
+
+	assert.ElementsMatch(t, []string{
+		"app.log",
+		"access.log",
+		"app-2020-01-01T02-00-00.000.log.gz",
+		"access-2020-01-01T03-00-00.000.log",
+	}, names())
+
+	// later rotations are pruned on the next tick
+	touch("app-2020-01-01T04-00-00.000.log.gz", time.Hour)
+	assert.Eventually(t, func() bool { return len(names()) == 4 }, time.Second, 5*time.Millisecond)
+	assert.NotContains(t, names(), "app-2020-01-01T02-00-00.000.log.gz")
+
+	stop()
+	stop()
+}
+
+func TestStartLogJanitorOnce(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test_log_janitor_once")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{
+		"app-2020-01-01T00-00-00.000.log",
+		"app-2020-01-01T01-00-00.000.log",
+		"app-2020-01-01T02-00-00.000.log",
+	} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), nil, 0644))
+	}
+
+	assert.NotPanics(t, func() { StartLogJanitor(dir, 1, 0)() })
+	fs, _ := ioutil.ReadDir(dir)
+	assert.Len(t, fs, 1)
+}
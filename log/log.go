@@ -1,12 +1,15 @@
 package log
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path"
+	"reflect"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/natefinch/lumberjack"
 
@@ -42,15 +45,152 @@ const (
 	TraceLevel
 )
 
+// Severity returns the RFC 5424 syslog severity corresponding to the level.
+func (level Level) Severity() int {
+	switch level {
+	case PanicLevel:
+		return 0
+	case FatalLevel:
+		return 2
+	case ErrorLevel:
+		return 3
+	case WarnLevel:
+		return 4
+	case InfoLevel:
+		return 6
+	}
+	return 7
+}
+
+// Rotation defaults used by Init when the corresponding Config field is unset.
+const (
+	DefaultMaxSize    = 500
+	DefaultMaxBackups = 3
+	DefaultMaxAge     = 28
+)
+
+// Output formats accepted by Config.Format.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Config describes how Init sets up the base logger.
+//
+// MaxSize is in megabytes and MaxAge in days. A zero value for MaxSize,
+// MaxBackups or MaxAge falls back to the Default* constants, as does a
+// negative MaxSize since lumberjack always rotates; set MaxBackups or
+// MaxAge to -1 to keep every rotated file regardless of count or age.
+// Compress and LocalTime default to true when omitted.
+//
+// Format selects FormatText (the default) or FormatJSON output.
+// Stdout defaults to true; set it to false to write to File only.
+//
+// TimestampFormat is a Go time layout and TimeZone an IANA zone name such as
+// "Asia/Shanghai"; an invalid TimeZone falls back to Local with a warning.
 type Config struct {
-	File  string `yaml:"File"`
-	Level string `yaml:"Level"`
+	File   string `yaml:"File"`
+	Level  string `yaml:"Level"`
+	Format string `yaml:"Format"`
+	Stdout *bool  `yaml:"Stdout"`
+
+	TimestampFormat string `yaml:"TimestampFormat"`
+	TimeZone        string `yaml:"TimeZone"`
+
+	MaxSize    int   `yaml:"MaxSize"`
+	MaxBackups int   `yaml:"MaxBackups"`
+	MaxAge     int   `yaml:"MaxAge"`
+	Compress   *bool `yaml:"Compress"`
+	LocalTime  *bool `yaml:"LocalTime"`
+}
+
+// rotation returns the lumberjack logger described by the config,
+// filling in defaults for unset fields.
+func (c Config) rotation(filename string) *lumberjack.Logger {
+	maxSize := c.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	return &lumberjack.Logger{
+		Filename:   filename,
+		MaxSize:    maxSize,
+		MaxBackups: orDefault(c.MaxBackups, DefaultMaxBackups),
+		MaxAge:     orDefault(c.MaxAge, DefaultMaxAge),
+		Compress:   c.Compress == nil || *c.Compress,
+		LocalTime:  c.LocalTime == nil || *c.LocalTime,
+	}
+}
+
+// orDefault maps zero to def and negative values to zero,
+// which lumberjack treats as unlimited.
+func orDefault(v, def int) int {
+	switch {
+	case v == 0:
+		return def
+	case v < 0:
+		return 0
+	}
+	return v
+}
+
+// output returns the writer described by the config.
+func (c Config) output() io.Writer {
+	if c.File == "" {
+		return os.Stdout
+	}
+
+	fp, fn := file.Dir(c.File), file.Basename(c.File)
+	if err := file.EnsureDirRW(fp); err != nil || (file.IsExist(c.File) && !file.IsFile(c.File)) {
+		fp, fn = "./", random.New().String(8, random.Lowercase)+".log"
+	}
+
+	rotated := c.rotation(path.Join(fp, fn))
+	if c.Stdout != nil && !*c.Stdout {
+		return rotated
+	}
+	return io.MultiWriter(os.Stdout, rotated)
+}
+
+// formatter returns the logrus formatter matching the configured format,
+// timestamp layout and time zone. The error reports an invalid TimeZone,
+// in which case timestamps are rendered in Local.
+func (c Config) formatter() (logrus.Formatter, error) {
+	var formatter logrus.Formatter
+	if strings.ToLower(c.Format) == FormatJSON {
+		formatter = &logrus.JSONFormatter{TimestampFormat: c.TimestampFormat}
+	} else {
+		formatter = &logrus.TextFormatter{TimestampFormat: c.TimestampFormat, FullTimestamp: c.TimestampFormat != ""}
+	}
+
+	if c.TimeZone == "" {
+		return formatter, nil
+	}
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		loc = time.Local
+	}
+	return zonedFormatter{Formatter: formatter, loc: loc}, err
+}
+
+// zonedFormatter renders entry timestamps in a fixed location.
+type zonedFormatter struct {
+	logrus.Formatter
+	loc *time.Location
+}
+
+func (f zonedFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	zoned := *entry
+	zoned.Time = entry.Time.In(f.loc)
+	return f.Formatter.Format(&zoned)
 }
 
 // Logger is an interface that describes logging.
 type Logger interface {
 	With(key string, value interface{}) Logger
+	WithFields(fields map[string]interface{}) Logger
 	WithError(err error) Logger
+	WithContext(ctx context.Context) Logger
+	WithCallerSkip(skip int) Logger
 
 	SetLevel(level Level)
 	SetOut(out io.Writer)
@@ -84,137 +224,198 @@ type Logger interface {
 }
 
 type logger struct {
-	entry *logrus.Entry
+	entry    *logrus.Entry
+	skip     int
+	omitNil  bool
+	severity bool
+	sampler  *sampler
+}
+
+// Option configures a logger created by New.
+type Option func(*logger)
+
+// OmitNilFields drops fields whose value is nil instead of logging "<nil>".
+func OmitNilFields() Option {
+	return func(l *logger) {
+		l.omitNil = true
+	}
+}
+
+// NumericSeverity adds a "severity" field holding the syslog severity
+// number of each entry's level, alongside the textual level.
+func NumericSeverity() Option {
+	return func(l *logger) {
+		l.severity = true
+	}
 }
 
 // With attaches a key-value pair to a logger.
 func (l logger) With(key string, value interface{}) Logger {
-	return logger{l.entry.WithField(key, value)}
+	if l.omitNil && isNil(value) {
+		return l
+	}
+	l.entry = l.entry.WithField(key, value)
+	return l
+}
+
+// WithFields attaches several key-value pairs to a logger at once.
+func (l logger) WithFields(fields map[string]interface{}) Logger {
+	data := make(logrus.Fields, len(fields))
+	for k, v := range fields {
+		if l.omitNil && isNil(v) {
+			continue
+		}
+		data[k] = v
+	}
+	l.entry = l.entry.WithFields(data)
+	return l
 }
 
-// WithError attaches an error to a logger.
+// WithError attaches an error to a logger. Fields carried by errors in
+// its chain that implement FieldsError are attached as well.
 func (l logger) WithError(err error) Logger {
-	return logger{l.entry.WithError(err)}
+	if fields := errorFields(err); len(fields) > 0 {
+		l = l.WithFields(fields).(logger)
+	}
+	l.entry = l.entry.WithError(err)
+	return l
+}
+
+// WithCallerSkip returns a logger whose "src" field skips additional
+// stack frames, for use by helpers that wrap a Logger.
+func (l logger) WithCallerSkip(skip int) Logger {
+	l.skip += skip
+	return l
 }
 
 // SetLevel sets the level of a logger.
 func (l logger) SetLevel(level Level) {
-	l.entry.Logger.Level = logrus.Level(level)
+	l.entry.Logger.SetLevel(logrus.Level(level))
 }
 
 // SetOut sets the output destination for a logger.
 func (l logger) SetOut(out io.Writer) {
-	l.entry.Logger.Out = out
+	l.entry.Logger.SetOutput(out)
 }
 
 // Trace logs a message at level Trace on the standard logger.
 func (l logger) Trace(args ...interface{}) {
-	l.sourced().Trace(args...)
+	l.sourced(TraceLevel, args...).Trace(args...)
 }
 
 // Debug logs a message at level Debug on the standard logger.
 func (l logger) Debug(args ...interface{}) {
-	l.sourced().Debug(args...)
+	l.sourced(DebugLevel, args...).Debug(args...)
 }
 
 // Print logs a message at level Print on the standard logger.
 func (l logger) Print(args ...interface{}) {
-	l.sourced().Print(args...)
+	l.sourced(InfoLevel, args...).Print(args...)
 }
 
 // Info logs a message at level Info on the standard logger.
 func (l logger) Info(args ...interface{}) {
-	l.sourced().Info(args...)
+	l.sourced(InfoLevel, args...).Info(args...)
 }
 
 // Warn logs a message at level Warn on the standard logger.
 func (l logger) Warn(args ...interface{}) {
-	l.sourced().Warn(args...)
+	l.sourced(WarnLevel, args...).Warn(args...)
 }
 
 // Error logs a message at Error Info on the standard logger.
 func (l logger) Error(args ...interface{}) {
-	l.sourced().Error(args...)
+	l.sourced(ErrorLevel, args...).Error(args...)
 }
 
 // Fatal logs a message at level Fatal on the standard logger.
 func (l logger) Fatal(args ...interface{}) {
-	l.sourced().Fatal(args...)
+	l.sourced(FatalLevel, args...).Fatal(args...)
 }
 
 // Panic logs a message at level Panic on the standard logger.
 func (l logger) Panic(args ...interface{}) {
-	l.sourced().Panic(args...)
+	l.sourced(PanicLevel, args...).Panic(args...)
 }
 
 func (l logger) Tracef(format string, args ...interface{}) {
-	l.sourced().Tracef(format, args...)
+	l.sourced(TraceLevel, format).Tracef(format, args...)
 }
 
 func (l logger) Debugf(format string, args ...interface{}) {
-	l.sourced().Debugf(format, args...)
+	l.sourced(DebugLevel, format).Debugf(format, args...)
 }
 
 func (l logger) Printf(format string, args ...interface{}) {
-	l.sourced().Printf(format, args...)
+	l.sourced(InfoLevel, format).Printf(format, args...)
 }
 
 func (l logger) Infof(format string, args ...interface{}) {
-	l.sourced().Infof(format, args...)
+	l.sourced(InfoLevel, format).Infof(format, args...)
 }
 
 func (l logger) Warnf(format string, args ...interface{}) {
-	l.sourced().Warnf(format, args...)
+	l.sourced(WarnLevel, format).Warnf(format, args...)
 }
 
 func (l logger) Errorf(format string, args ...interface{}) {
-	l.sourced().Errorf(format, args...)
+	l.sourced(ErrorLevel, format).Errorf(format, args...)
 }
 
 func (l logger) Fatalf(format string, args ...interface{}) {
-	l.sourced().Fatalf(format, args...)
+	l.sourced(FatalLevel, format).Fatalf(format, args...)
 }
 
 func (l logger) Panicf(format string, args ...interface{}) {
-	l.sourced().Panicf(format, args...)
+	l.sourced(PanicLevel, format).Panicf(format, args...)
 }
 
 func (l logger) Traceln(args ...interface{}) {
-	l.sourced().Traceln(args...)
+	l.sourced(TraceLevel, args...).Traceln(args...)
 }
 
 func (l logger) Debugln(args ...interface{}) {
-	l.sourced().Debugln(args...)
+	l.sourced(DebugLevel, args...).Debugln(args...)
 }
 
 func (l logger) Println(args ...interface{}) {
-	l.sourced().Println(args...)
+	l.sourced(InfoLevel, args...).Println(args...)
 }
 
 func (l logger) Infoln(args ...interface{}) {
-	l.sourced().Infoln(args...)
+	l.sourced(InfoLevel, args...).Infoln(args...)
 }
 
 func (l logger) Warnln(args ...interface{}) {
-	l.sourced().Warnln(args...)
+	l.sourced(WarnLevel, args...).Warnln(args...)
 }
 
 func (l logger) Errorln(args ...interface{}) {
-	l.sourced().Errorln(args...)
+	l.sourced(ErrorLevel, args...).Errorln(args...)
 }
 
 func (l logger) Fatalln(args ...interface{}) {
-	l.sourced().Fatalln(args...)
+	l.sourced(FatalLevel, args...).Fatalln(args...)
 }
 
 func (l logger) Panicln(args ...interface{}) {
-	l.sourced().Panicln(args...)
+	l.sourced(PanicLevel, args...).Panicln(args...)
 }
 
 // sourced adds a source field to the logger that contains
 // the file name and line where the logging happened.
-func (l logger) sourced() *logrus.Entry {
-	_, _file, line, ok := runtime.Caller(2)
+// msg is the format string or the arguments of the call, used as
+// the sampling key.
+func (l logger) sourced(level Level, msg ...interface{}) *logrus.Entry {
+	// sample first so that suppressed lines do not use up the global budget
+	if l.sampler != nil && !l.sampler.allow(l.entry.Logger, level, msg) {
+		return discarded
+	}
+	if !allowGlobal(l.entry.Logger, level) {
+		return discarded
+	}
+
+	_, _file, line, ok := runtime.Caller(2 + l.skip)
 
 	if !ok {
 		_file = "<???>"
@@ -224,15 +425,40 @@ func (l logger) sourced() *logrus.Entry {
 		_file = _file[slash+1:]
 	}
 
-	return l.entry.WithField("src", fmt.Sprintf("%s:%d", _file, line))
+	entry := l.entry.WithField("src", fmt.Sprintf("%s:%d", _file, line))
+	if l.severity {
+		entry = entry.WithField("severity", level.Severity())
+	}
+	if env := Environment(); env != "" {
+		entry = entry.WithField(EnvironmentField, env)
+	}
+	return entry
+}
+
+// isNil reports whether v is nil or a nil pointer, map, slice, etc.
+// boxed in an interface.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
 }
 
 var origLogger = logrus.New()
 var baseLogger = logger{entry: logrus.NewEntry(origLogger)}
 
-// New returns a new logger.
-func New() Logger {
-	return logger{entry: logrus.NewEntry(origLogger)}
+// New returns a new logger configured with opts. It shares level, output,
+// formatter and hooks with the base logger.
+func New(opts ...Option) Logger {
+	l := logger{entry: logrus.NewEntry(origLogger)}
+	for _, opt := range opts {
+		opt(&l)
+	}
+	return l
 }
 
 // Base returns the base logger.
@@ -242,144 +468,148 @@ func Base() Logger {
 
 // Initialize the logger with config
 // When path is not legal, the current path will be used.
-// Multiwriter by default
+// When File is empty, logs are written to stdout only.
+// Multiwriter by default, unless Stdout is set to false.
 func Init(config Config) {
-	fp, fn := file.Dir(config.File), file.Basename(config.File)
-	if err := file.EnsureDirRW(fp); err != nil || (file.IsExist(config.File) && !file.IsFile(config.File))  {
-		fp, fn = "./", random.New().String(8, random.Lowercase)+".log"
-	}
-
 	level, err := logrus.ParseLevel(config.Level)
 	if err != nil {
 		level = logrus.InfoLevel
 	}
 
+	formatter, zoneErr := config.formatter()
 	SetLevel(Level(level))
-	SetOut(io.MultiWriter(os.Stdout, &lumberjack.Logger{
-		Filename:   path.Join(fp, fn),
-		MaxSize:    500,
-		MaxBackups: 3,
-		MaxAge:     28,
-		Compress:   true,
-		LocalTime:  true,
-	}))
+	SetFormatter(formatter)
+	SetOut(config.output())
+
+	if zoneErr != nil {
+		Warnf("invalid TimeZone %q, using Local: %s", config.TimeZone, zoneErr)
+	}
 }
 
 // SetLevel sets the Level of the base logger
 func SetLevel(level Level) {
-	baseLogger.entry.Logger.Level = logrus.Level(level)
+	baseLogger.entry.Logger.SetLevel(logrus.Level(level))
 }
 
 // GetLevel gets the level of a logger.
 func GetLevel() Level {
-	return Level(baseLogger.entry.Logger.Level)
+	return Level(baseLogger.entry.Logger.GetLevel())
 }
 
 // SetOut sets the output destination base logger
 func SetOut(out io.Writer) {
-	baseLogger.entry.Logger.Out = out
+	baseLogger.entry.Logger.SetOutput(out)
+}
+
+// SetFormatter sets the formatter of the base logger
+func SetFormatter(formatter logrus.Formatter) {
+	baseLogger.entry.Logger.SetFormatter(formatter)
 }
 
 func With(key string, value interface{}) Logger {
 	return baseLogger.With(key, value)
 }
 
+func WithFields(fields map[string]interface{}) Logger {
+	return baseLogger.WithFields(fields)
+}
+
 func WithError(err error) Logger {
-	return logger{entry: baseLogger.sourced().WithError(err)}
+	return baseLogger.WithError(err)
 }
 
 func Trace(args ...interface{}) {
-	baseLogger.sourced().Trace(args...)
+	baseLogger.sourced(TraceLevel, args...).Trace(args...)
 }
 
 func Tracef(format string, args ...interface{}) {
-	baseLogger.sourced().Tracef(format, args...)
+	baseLogger.sourced(TraceLevel, format).Tracef(format, args...)
 }
 
 func Traceln(args ...interface{}) {
-	baseLogger.sourced().Traceln(args...)
+	baseLogger.sourced(TraceLevel, args...).Traceln(args...)
 }
 
 func Debug(args ...interface{}) {
-	baseLogger.sourced().Debug(args...)
+	baseLogger.sourced(DebugLevel, args...).Debug(args...)
 }
 
 func Debugf(format string, args ...interface{}) {
-	baseLogger.sourced().Debugf(format, args...)
+	baseLogger.sourced(DebugLevel, format).Debugf(format, args...)
 }
 
 func Debugln(args ...interface{}) {
-	baseLogger.sourced().Debugln(args...)
+	baseLogger.sourced(DebugLevel, args...).Debugln(args...)
 }
 
 func Print(args ...interface{}) {
-	baseLogger.sourced().Print(args...)
+	baseLogger.sourced(InfoLevel, args...).Print(args...)
 }
 
 func Printf(format string, args ...interface{}) {
-	baseLogger.sourced().Printf(format, args...)
+	baseLogger.sourced(InfoLevel, format).Printf(format, args...)
 }
 
 func Println(args ...interface{}) {
-	baseLogger.sourced().Println(args...)
+	baseLogger.sourced(InfoLevel, args...).Println(args...)
 }
 
 func Info(args ...interface{}) {
-	baseLogger.sourced().Info(args...)
+	baseLogger.sourced(InfoLevel, args...).Info(args...)
 }
 
 func Infof(format string, args ...interface{}) {
-	baseLogger.sourced().Infof(format, args...)
+	baseLogger.sourced(InfoLevel, format).Infof(format, args...)
 }
 
 func Infoln(args ...interface{}) {
-	baseLogger.sourced().Infoln(args...)
+	baseLogger.sourced(InfoLevel, args...).Infoln(args...)
 }
 
 func Warn(args ...interface{}) {
-	baseLogger.sourced().Warn(args...)
+	baseLogger.sourced(WarnLevel, args...).Warn(args...)
 }
 
 func Warnf(format string, args ...interface{}) {
-	baseLogger.sourced().Warnf(format, args...)
+	baseLogger.sourced(WarnLevel, format).Warnf(format, args...)
 }
 
 func Warnln(args ...interface{}) {
-	baseLogger.sourced().Warnln(args...)
+	baseLogger.sourced(WarnLevel, args...).Warnln(args...)
 }
 
 func Error(args ...interface{}) {
-	baseLogger.sourced().Error(args...)
+	baseLogger.sourced(ErrorLevel, args...).Error(args...)
 }
 
 func Errorf(format string, args ...interface{}) {
-	baseLogger.sourced().Errorf(format, args...)
+	baseLogger.sourced(ErrorLevel, format).Errorf(format, args...)
 }
 
 func Errorln(args ...interface{}) {
-	baseLogger.sourced().Errorln(args...)
+	baseLogger.sourced(ErrorLevel, args...).Errorln(args...)
 }
 
 func Fatal(args ...interface{}) {
-	baseLogger.sourced().Fatal(args...)
+	baseLogger.sourced(FatalLevel, args...).Fatal(args...)
 }
 
 func Fatalf(format string, args ...interface{}) {
-	baseLogger.sourced().Fatalf(format, args...)
+	baseLogger.sourced(FatalLevel, format).Fatalf(format, args...)
 }
 
 func Fatalln(args ...interface{}) {
-	baseLogger.sourced().Fatalln(args...)
+	baseLogger.sourced(FatalLevel, args...).Fatalln(args...)
 }
 
 func Panic(args ...interface{}) {
-	baseLogger.sourced().Panic(args...)
+	baseLogger.sourced(PanicLevel, args...).Panic(args...)
 }
 
 func Panicf(format string, args ...interface{}) {
-	baseLogger.sourced().Panicf(format, args...)
+	baseLogger.sourced(PanicLevel, format).Panicf(format, args...)
 }
 
 func Panicln(args ...interface{}) {
-	baseLogger.sourced().Panicln(args...)
+	baseLogger.sourced(PanicLevel, args...).Panicln(args...)
 }
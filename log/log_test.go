@@ -0,0 +1,200 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/natefinch/lumberjack"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigRotation(t *testing.T) {
+	r := Config{}.rotation("app.log")
+	assert.Equal(t, "app.log", r.Filename)
+	assert.Equal(t, DefaultMaxSize, r.MaxSize)
+	assert.Equal(t, DefaultMaxBackups, r.MaxBackups)
+	assert.Equal(t, DefaultMaxAge, r.MaxAge)
+	assert.True(t, r.Compress)
+	assert.True(t, r.LocalTime)
+
+	off := false
+	r = Config{MaxSize: 10, MaxBackups: -1, MaxAge: 365, Compress: &off, LocalTime: &off}.rotation("app.log")
+	assert.Equal(t, 10, r.MaxSize)
+	assert.Equal(t, 0, r.MaxBackups)
+	assert.Equal(t, 365, r.MaxAge)
+	assert.False(t, r.Compress)
+	assert.False(t, r.LocalTime)
+
+	r = Config{MaxSize: -1, MaxAge: -1}.rotation("app.log")
+	assert.Equal(t, DefaultMaxSize, r.MaxSize)
+	assert.Equal(t, 0, r.MaxAge)
+}
+
+func TestConfigFormatter(t *testing.T) {
+	formatter, err := Config{}.formatter()
+	assert.NoError(t, err)
+	assert.IsType(t, &logrus.TextFormatter{}, formatter)
+
+	formatter, _ = Config{Format: "xml"}.formatter()
+	assert.IsType(t, &logrus.TextFormatter{}, formatter)
+
+	formatter, _ = Config{Format: "JSON"}.formatter()
+	assert.IsType(t, &logrus.JSONFormatter{}, formatter)
+}
+
+func TestConfigTimeZone(t *testing.T) {
+	entry := &logrus.Entry{
+		Logger:  logrus.New(),
+		Time:    time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:   logrus.InfoLevel,
+		Message: "zoned",
+	}
+
+	for _, format := range []string{FormatText, FormatJSON} {
+		formatter, err := Config{Format: format, TimestampFormat: "2006-01-02 15:04:05 MST", TimeZone: "Asia/Shanghai"}.formatter()
+		assert.NoError(t, err)
+		out, err := formatter.Format(entry)
+		assert.NoError(t, err)
+		assert.Contains(t, string(out), "2020-01-02 11:04:05 CST", format)
+	}
+	assert.Equal(t, time.UTC, entry.Time.Location())
+
+	formatter, err := Config{TimeZone: "Mars/Olympus_Mons"}.formatter()
+	assert.Error(t, err)
+	assert.Equal(t, time.Local, formatter.(zonedFormatter).loc)
+}
+
+func TestSetFormatterJSON(t *testing.T) {
+	defer SetFormatter(&logrus.TextFormatter{})
+	defer SetOut(os.Stderr)
+
+	var buf bytes.Buffer
+	SetOut(&buf)
+	SetFormatter(&logrus.JSONFormatter{})
+	New().Info("hello")
+
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "hello", line["msg"])
+	assert.Regexp(t, `^log_test\.go:\d+$`, line["src"])
+}
+
+func TestConfigOutput(t *testing.T) {
+	assert.Equal(t, os.Stdout, Config{}.output())
+
+	dir, err := ioutil.TempDir("", "log")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	off := false
+	out := Config{File: filepath.Join(dir, "app.log"), Stdout: &off}.output()
+	if assert.IsType(t, &lumberjack.Logger{}, out) {
+		assert.Equal(t, filepath.Join(dir, "app.log"), out.(*lumberjack.Logger).Filename)
+	}
+
+	out = Config{File: filepath.Join(dir, "app.log")}.output()
+	_, rotated := out.(*lumberjack.Logger)
+	assert.False(t, rotated)
+	assert.NotEqual(t, os.Stdout, out)
+}
+
+func TestOmitNilFields(t *testing.T) {
+	defer SetOut(os.Stderr)
+
+	var buf bytes.Buffer
+	SetOut(&buf)
+
+	var missing *int
+	New(OmitNilFields()).With("gone", nil).With("typed", missing).With("kept", 1).Info("nil fields")
+	assert.NotContains(t, buf.String(), "gone=")
+	assert.NotContains(t, buf.String(), "typed=")
+	assert.Contains(t, buf.String(), "kept=1")
+
+	buf.Reset()
+	New().With("shown", nil).Info("nil fields")
+	assert.Contains(t, buf.String(), "shown=")
+}
+
+// capture runs fn with the base logger writing JSON into a buffer
+// and returns the decoded entry.
+func capture(t *testing.T, fn func()) map[string]interface{} {
+	defer SetOut(os.Stderr)
+	defer SetFormatter(&logrus.TextFormatter{})
+
+	var buf bytes.Buffer
+	SetOut(&buf)
+	SetFormatter(&logrus.JSONFormatter{})
+	fn()
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	return entry
+}
+
+func infoOnce(l Logger, msg string) {
+	l.WithCallerSkip(1).Info(msg)
+}
+
+func infoTwice(l Logger, msg string) {
+	infoOnce(l.WithCallerSkip(1), msg)
+}
+
+func TestWithCallerSkip(t *testing.T) {
+	var line int
+	entry := capture(t, func() {
+		New().Info("direct")
+		_, _, line, _ = runtime.Caller(0)
+	})
+	assert.Equal(t, fmt.Sprintf("log_test.go:%d", line-1), entry["src"])
+
+	entry = capture(t, func() {
+		infoOnce(New(), "one level")
+		_, _, line, _ = runtime.Caller(0)
+	})
+	assert.Equal(t, fmt.Sprintf("log_test.go:%d", line-1), entry["src"])
+
+	entry = capture(t, func() {
+		infoTwice(New(), "two levels")
+		_, _, line, _ = runtime.Caller(0)
+	})
+	assert.Equal(t, fmt.Sprintf("log_test.go:%d", line-1), entry["src"])
+}
+
+func TestWithFields(t *testing.T) {
+	entry := capture(t, func() {
+		WithFields(map[string]interface{}{"method": "GET", "status": 200}).Info("request")
+	})
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, float64(200), entry["status"])
+
+	entry = capture(t, func() {
+		New(OmitNilFields()).WithFields(map[string]interface{}{"user": nil, "path": "/"}).Info("request")
+	})
+	assert.NotContains(t, entry, "user")
+	assert.Equal(t, "/", entry["path"])
+}
+
+func TestNumericSeverity(t *testing.T) {
+	entry := capture(t, func() {
+		New(NumericSeverity()).Error("failed")
+	})
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, float64(3), entry["severity"])
+
+	entry = capture(t, func() {
+		New().Error("failed")
+	})
+	assert.NotContains(t, entry, "severity")
+
+	assert.Equal(t, 4, WarnLevel.Severity())
+	assert.Equal(t, 6, InfoLevel.Severity())
+	assert.Equal(t, 7, TraceLevel.Severity())
+}
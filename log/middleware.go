@@ -0,0 +1,121 @@
+package log
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/srelab/common/random"
+)
+
+// HTTPMiddleware reads the correlation ID from the named request header,
+// generating one when it is absent, and stores it in the request context so
+// that WithContext(r.Context()) tags every entry of the request with it.
+// The ID is echoed back in the same response header.
+func HTTPMiddleware(header string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(header)
+			if id == "" {
+				id = random.String(16, random.Hex)
+			}
+
+			w.Header().Set(header, id)
+			next.ServeHTTP(w, r.WithContext(ContextWithCorrelationID(r.Context(), id)))
+		})
+	}
+}
+
+// AccessLogMiddleware logs one Info entry per request with its method, path,
+// status, response bytes and duration. Entries go through WithContext, so
+// they carry the correlation ID when HTTPMiddleware runs first.
+func AccessLogMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			WithContext(r.Context()).WithFields(map[string]interface{}{
+				"method":   r.Method,
+				"path":     r.URL.Path,
+				"status":   rec.statusCode(),
+				"bytes":    rec.bytes,
+				"duration": time.Since(start),
+			}).Info("access")
+		})
+	}
+}
+
+// responseRecorder captures the status and size of a response. It forwards
+// http.Flusher, http.Hijacker and http.Pusher to the wrapped writer, and
+// exposes it to http.ResponseController through Unwrap, so that streaming
+// and websocket handlers keep working behind the middleware.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher. It is a no-op if the wrapped writer
+// cannot flush.
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		if r.status == 0 {
+			r.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker. A hijacked connection is logged with
+// status 101 unless the handler already wrote a status.
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T does not implement http.Hijacker", r.ResponseWriter)
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Push implements http.Pusher.
+func (r *responseRecorder) Push(target string, opts *http.PushOptions) error {
+	if p, ok := r.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *responseRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
@@ -0,0 +1,110 @@
+package log
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	handler := HTTPMiddleware("X-Request-ID")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WithContext(r.Context()).Info("handled")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "abc-123")
+	rec := httptest.NewRecorder()
+	entry := capture(t, func() {
+		handler.ServeHTTP(rec, req)
+	})
+	assert.Equal(t, "abc-123", entry[CorrelationIDField])
+	assert.Equal(t, "abc-123", rec.Header().Get("X-Request-ID"))
+
+	rec = httptest.NewRecorder()
+	entry = capture(t, func() {
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	assert.Regexp(t, "^[0-9a-f]{16}$", entry[CorrelationIDField])
+	assert.Equal(t, entry[CorrelationIDField], rec.Header().Get("X-Request-ID"))
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	handler := HTTPMiddleware("X-Request-ID")(AccessLogMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("short and stout"))
+	})))
+
+	req := httptest.NewRequest(http.MethodPost, "/pots/1?brew=1", nil)
+	req.Header.Set("X-Request-ID", "abc-123")
+	rec := httptest.NewRecorder()
+	entry := capture(t, func() {
+		handler.ServeHTTP(rec, req)
+	})
+
+	assert.Equal(t, "access", entry["msg"])
+	assert.Equal(t, "POST", entry["method"])
+	assert.Equal(t, "/pots/1", entry["path"])
+	assert.Equal(t, float64(rec.Code), entry["status"])
+	assert.Equal(t, float64(rec.Body.Len()), entry["bytes"])
+	assert.Contains(t, entry, "duration")
+	assert.Equal(t, "abc-123", entry[CorrelationIDField])
+
+	entry = capture(t, func() {
+		AccessLogMiddleware()(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	})
+	assert.Equal(t, float64(http.StatusNotFound), entry["status"])
+}
+
+func TestAccessLogMiddlewareStreaming(t *testing.T) {
+	// a streaming handler can still flush through the recorder
+	handler := AccessLogMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("event: ping\n\n"))
+		w.(http.Flusher).Flush()
+	}))
+	rec := httptest.NewRecorder()
+	entry := capture(t, func() {
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+	})
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, float64(http.StatusOK), entry["status"])
+
+	// and a websocket-style handler can take over the connection
+	done := make(chan struct{})
+	inner := AccessLogMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: test\r\nConnection: Upgrade\r\n\r\n")
+		rw.Flush()
+	}))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		inner.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	entry = capture(t, func() {
+		conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		fmt.Fprintf(conn, "GET /ws HTTP/1.1\r\nHost: test\r\nUpgrade: test\r\nConnection: Upgrade\r\n\r\n")
+		status, err := bufio.NewReader(conn).ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "HTTP/1.1 101 Switching Protocols\r\n", status)
+		<-done
+	})
+	assert.Equal(t, float64(http.StatusSwitchingProtocols), entry["status"])
+
+	var w http.ResponseWriter = &responseRecorder{ResponseWriter: rec}
+	assert.Equal(t, http.ErrNotSupported, w.(http.Pusher).Push("/style.css", nil))
+	assert.Equal(t, rec, w.(interface{ Unwrap() http.ResponseWriter }).Unwrap())
+}
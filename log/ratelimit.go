@@ -0,0 +1,125 @@
+package log
+
+import (
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// discarded is handed out by sourced for entries that must be dropped.
+// Its logger has every level disabled, so logging through it is a no-op.
+var discarded = logrus.NewEntry(&logrus.Logger{
+	Out:       ioutil.Discard,
+	Formatter: new(logrus.TextFormatter),
+	Hooks:     make(logrus.LevelHooks),
+	Level:     logrus.PanicLevel,
+	ExitFunc:  func(int) {},
+})
+
+// timeNow is replaced in tests.
+var timeNow = time.Now
+
+// rateLimitSummary is the delay after the first dropped line before the
+// number of dropped lines is reported, unless a line logged in a later
+// second reports it first. Replaced in tests.
+var rateLimitSummary = time.Second
+
+type rateLimiter struct {
+	level     Level
+	perSecond int
+
+	mu      sync.Mutex
+	window  int64
+	count   int
+	dropped int
+	logger  *logrus.Logger
+	timer   *time.Timer
+}
+
+// globalLimit holds the *rateLimiter installed by SetGlobalRateLimit.
+var globalLimit atomic.Value
+
+// SetGlobalRateLimit caps the number of lines emitted per second, across
+// every logger in the process, for level and all more verbose levels.
+// Excess lines are dropped; the number dropped is reported by a warning
+// emitted before the first line of the next second that logs or, if the
+// flood has stopped, a second after the first line was dropped. Fatal and
+// Panic lines are never dropped. A perSecond of 0 or less removes the
+// limit. Changing the limit reports the lines dropped under the old one.
+func SetGlobalRateLimit(level Level, perSecond int) {
+	rl := (*rateLimiter)(nil)
+	if perSecond > 0 {
+		rl = &rateLimiter{level: level, perSecond: perSecond}
+	}
+	if old, _ := globalLimit.Swap(rl).(*rateLimiter); old != nil {
+		old.flush()
+	}
+}
+
+// allowGlobal reports whether an entry at level may be written by logger
+// under the global rate limit.
+func allowGlobal(logger *logrus.Logger, level Level) bool {
+	rl, _ := globalLimit.Load().(*rateLimiter)
+	if rl == nil || level <= FatalLevel || level < rl.level {
+		return true
+	}
+	if !logger.IsLevelEnabled(logrus.Level(level)) {
+		return true
+	}
+
+	allowed, dropped := rl.take(logger, timeNow().Unix())
+	reportDropped(logger, dropped)
+	return allowed
+}
+
+// take consumes one slot in the current one-second window. It also returns
+// the number of lines dropped and not yet reported when a new window
+// starts. The first line it drops schedules flush to report the count
+// should no new window start.
+func (rl *rateLimiter) take(logger *logrus.Logger, sec int64) (allowed bool, dropped int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if sec != rl.window {
+		dropped = rl.dropped
+		rl.window, rl.count, rl.dropped = sec, 0, 0
+		rl.stopTimer()
+	}
+	if rl.count >= rl.perSecond {
+		rl.dropped++
+		rl.logger = logger
+		if rl.timer == nil {
+			rl.timer = time.AfterFunc(rateLimitSummary, rl.flush)
+		}
+		return false, dropped
+	}
+	rl.count++
+	return true, dropped
+}
+
+// flush reports the lines dropped and not yet reported.
+func (rl *rateLimiter) flush() {
+	rl.mu.Lock()
+	rl.stopTimer()
+	dropped, logger := rl.dropped, rl.logger
+	rl.dropped = 0
+	rl.mu.Unlock()
+
+	reportDropped(logger, dropped)
+}
+
+func (rl *rateLimiter) stopTimer() {
+	if rl.timer != nil {
+		rl.timer.Stop()
+		rl.timer = nil
+	}
+}
+
+func reportDropped(logger *logrus.Logger, dropped int) {
+	if dropped > 0 {
+		logger.WithField("dropped", dropped).Warnf("rate limit: dropped %d lines", dropped)
+	}
+}
@@ -0,0 +1,98 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetGlobalRateLimit(t *testing.T) {
+	defer SetOut(os.Stderr)
+	defer SetGlobalRateLimit(InfoLevel, 0)
+	defer func() { timeNow = time.Now }()
+
+	now := time.Unix(1500000000, 0)
+	timeNow = func() time.Time { return now }
+
+	var buf bytes.Buffer
+	SetOut(&buf)
+	SetGlobalRateLimit(InfoLevel, 10)
+
+	l := New()
+	for i := 0; i < 1000; i++ {
+		l.Info("flood")
+		Info("flood")
+	}
+	Error("still logged")
+	assert.Equal(t, 10, strings.Count(buf.String(), "msg=flood"))
+	assert.Contains(t, buf.String(), "still logged")
+
+	buf.Reset()
+	now = now.Add(time.Second)
+	for i := 0; i < 1000; i++ {
+		l.Info("flood")
+	}
+	assert.Equal(t, 10, strings.Count(buf.String(), "msg=flood"))
+	assert.Contains(t, buf.String(), "dropped=1990")
+
+	buf.Reset()
+	SetGlobalRateLimit(InfoLevel, 0)
+	for i := 0; i < 100; i++ {
+		l.Info("flood")
+	}
+	assert.Equal(t, 100, strings.Count(buf.String(), "msg=flood"))
+}
+
+// syncBuffer is a bytes.Buffer safe to write from the summary timer while
+// the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestGlobalRateLimitSummary(t *testing.T) {
+	defer SetOut(os.Stderr)
+	defer SetGlobalRateLimit(InfoLevel, 0)
+	defer func(d time.Duration) { rateLimitSummary = d }(rateLimitSummary)
+	rateLimitSummary = 20 * time.Millisecond
+	defer func() { timeNow = time.Now }()
+	timeNow = func() time.Time { return time.Unix(1500000000, 0) }
+
+	var buf syncBuffer
+	SetOut(&buf)
+	SetGlobalRateLimit(InfoLevel, 10)
+
+	// the flood stops within the second: the count is still reported
+	for i := 0; i < 100; i++ {
+		Info("flood")
+	}
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "dropped=90")
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 1, strings.Count(buf.String(), "rate limit"))
+
+	// lines dropped before the limit is changed are reported at once
+	for i := 0; i < 100; i++ {
+		Info("flood")
+	}
+	SetGlobalRateLimit(InfoLevel, 0)
+	assert.Equal(t, 2, strings.Count(buf.String(), "rate limit"))
+	assert.Contains(t, buf.String(), "dropped=100")
+}
@@ -0,0 +1,136 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NewSampled returns a logger that lets at most every identical messages
+// through per interval and drops the rest. Messages are identified by level
+// and format string (or message text for the non-f variants), so varying
+// arguments do not defeat sampling. The number of copies suppressed is
+// reported in a warning when the next copy arrives after the interval, or
+// one interval after the first copy was suppressed if none does.
+// Fatal and Panic messages are never sampled. An every below 1 is treated
+// as 1, and a per of 0 or less disables sampling.
+//
+// Loggers derived from the returned one through With and friends share its
+// sampling state. Loggers created by New are not sampled and pay no cost.
+func NewSampled(every int, per time.Duration, opts ...Option) Logger {
+	l := New(opts...).(logger)
+	if per <= 0 {
+		return l
+	}
+	if every < 1 {
+		every = 1
+	}
+	l.sampler = &sampler{every: every, per: per, windows: make(map[sampleKey]*sampleWindow)}
+	return l
+}
+
+type sampleKey struct {
+	level Level
+	msg   string
+}
+
+type sampleWindow struct {
+	start      time.Time
+	count      int
+	suppressed int
+}
+
+type sampler struct {
+	every int
+	per   time.Duration
+
+	mu        sync.Mutex
+	windows   map[sampleKey]*sampleWindow
+	lastSweep time.Time
+	logger    *logrus.Logger
+	timer     *time.Timer
+}
+
+// allow reports whether a message may be written, and reports suppressed
+// counts of expired windows through logger.
+func (s *sampler) allow(logger *logrus.Logger, level Level, msg []interface{}) bool {
+	if level <= FatalLevel || !logger.IsLevelEnabled(logrus.Level(level)) {
+		return true
+	}
+
+	key := sampleKey{level, fmt.Sprint(msg...)}
+	now := timeNow()
+
+	s.mu.Lock()
+	expired := s.sweep(now)
+	w := s.windows[key]
+	if w == nil || now.Sub(w.start) >= s.per {
+		if w != nil && w.suppressed > 0 {
+			expired[key] = w.suppressed
+		}
+		w = &sampleWindow{start: now}
+		s.windows[key] = w
+	}
+	allowed := w.count < s.every
+	if allowed {
+		w.count++
+	} else {
+		w.suppressed++
+		s.logger = logger
+		if s.timer == nil {
+			s.timer = time.AfterFunc(s.per, s.flush)
+		}
+	}
+	s.mu.Unlock()
+
+	reportSuppressed(logger, expired)
+	return allowed
+}
+
+// flush reports the counts suppressed so far in every window, so that they
+// are not lost when the messages stop.
+func (s *sampler) flush() {
+	expired := make(map[sampleKey]int)
+
+	s.mu.Lock()
+	s.timer = nil
+	for k, w := range s.windows {
+		if w.suppressed > 0 {
+			expired[k] = w.suppressed
+			w.suppressed = 0
+		}
+	}
+	logger := s.logger
+	s.mu.Unlock()
+
+	reportSuppressed(logger, expired)
+}
+
+func reportSuppressed(logger *logrus.Logger, expired map[sampleKey]int) {
+	for k, n := range expired {
+		logger.WithField("suppressed", n).Warnf("...suppressed %d messages: %s", n, k.msg)
+	}
+}
+
+// sweep drops windows that ended more than one interval ago, so keys that
+// stop occurring do not accumulate, and returns their suppressed counts.
+// It runs at most once per interval and must be called with s.mu held.
+func (s *sampler) sweep(now time.Time) map[sampleKey]int {
+	expired := make(map[sampleKey]int)
+	if now.Sub(s.lastSweep) < s.per {
+		return expired
+	}
+	s.lastSweep = now
+
+	for k, w := range s.windows {
+		if now.Sub(w.start) >= s.per {
+			if w.suppressed > 0 {
+				expired[k] = w.suppressed
+			}
+			delete(s.windows, k)
+		}
+	}
+	return expired
+}
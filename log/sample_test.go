@@ -0,0 +1,105 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSampled(t *testing.T) {
+	defer SetOut(os.Stderr)
+	defer func() { timeNow = time.Now }()
+
+	now := time.Unix(1500000000, 0)
+	timeNow = func() time.Time { return now }
+
+	var buf bytes.Buffer
+	SetOut(&buf)
+
+	l := NewSampled(3, time.Second)
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				l.With("goroutine", g).Errorf("query failed: id=%d", i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	l.Info("other message")
+
+	assert.Equal(t, 3, strings.Count(buf.String(), "query failed"))
+	assert.Equal(t, 1, strings.Count(buf.String(), "other message"))
+
+	buf.Reset()
+	now = now.Add(time.Second)
+	l.Errorf("query failed: id=%d", 1000)
+	assert.Contains(t, buf.String(), "suppressed=997")
+	assert.Equal(t, 2, strings.Count(buf.String(), "query failed"))
+
+	buf.Reset()
+	for i := 0; i < 10; i++ {
+		New().Errorf("query failed: id=%d", i)
+	}
+	assert.Equal(t, 10, strings.Count(buf.String(), "query failed"))
+}
+
+func TestNewSampledWithGlobalRateLimit(t *testing.T) {
+	defer SetOut(os.Stderr)
+	defer SetGlobalRateLimit(InfoLevel, 0)
+	defer func() { timeNow = time.Now }()
+
+	now := time.Unix(1500000000, 0)
+	timeNow = func() time.Time { return now }
+
+	var buf bytes.Buffer
+	SetOut(&buf)
+	SetGlobalRateLimit(InfoLevel, 10)
+
+	l := NewSampled(1, time.Hour)
+	for i := 0; i < 50; i++ {
+		l.Info("noisy")
+	}
+	New().Info("important")
+
+	assert.Equal(t, 1, strings.Count(buf.String(), "msg=noisy"))
+	assert.Contains(t, buf.String(), "important")
+}
+
+func TestNewSampledSummary(t *testing.T) {
+	defer SetOut(os.Stderr)
+
+	var buf syncBuffer
+	SetOut(&buf)
+
+	// the flood stops: the count is reported once the interval has passed
+	l := NewSampled(1, 20*time.Millisecond)
+	for i := 0; i < 50; i++ {
+		l.Info("flood")
+	}
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "suppressed=49")
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 1, strings.Count(buf.String(), "msg=flood"))
+
+	// every below 1 still lets one copy through
+	l = NewSampled(0, time.Hour)
+	for i := 0; i < 5; i++ {
+		l.Info("at least one")
+	}
+	assert.Equal(t, 1, strings.Count(buf.String(), "msg=\"at least one\""))
+
+	// a non-positive interval disables sampling
+	l = NewSampled(1, 0)
+	for i := 0; i < 5; i++ {
+		l.Info("unsampled")
+	}
+	assert.Equal(t, 5, strings.Count(buf.String(), "msg=unsampled"))
+}
@@ -0,0 +1,193 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/srelab/common/random"
+)
+
+const (
+	// syslogFacility is the user-level messages facility.
+	syslogFacility = 1
+	// syslogSDID names the structured data element carrying entry fields.
+	// 32473 is the enterprise number reserved for documentation by RFC 5612.
+	syslogSDID = "fields@32473"
+
+	syslogTimeout = 5 * time.Second
+	// syslogBacklog is the number of messages queued for the collector
+	// before new ones are dropped.
+	syslogBacklog = 1024
+)
+
+var syslogEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// AddSyslogWriter sends the entries logged at levels, or at every level
+// when levels is empty, to the syslog collector at addr as RFC 5424
+// messages tagged with tag, in addition to the existing outputs. network
+// is "udp" or "tcp"; TCP messages are framed by octet counting (RFC 6587).
+// Entry fields are sent as structured data. tag is used as the APP-NAME,
+// truncated to 48 bytes and with characters other than printable ASCII,
+// including spaces, replaced by '_'.
+//
+// An error is returned only if the collector cannot be dialed. Once added,
+// messages are sent from a background goroutine so that a slow or
+// unreachable collector never blocks local logging: messages are dropped
+// when its backlog is full, and a lost connection is re-dialed with
+// exponential backoff, dropping messages in the meantime.
+func AddSyslogWriter(network, addr, tag string, levels []Level) error {
+	conn, err := net.DialTimeout(network, addr, syslogTimeout)
+	if err != nil {
+		return err
+	}
+
+	// printableASCII turns an unknown hostname into the NILVALUE
+	hostname, _ := os.Hostname()
+
+	h := &syslogHook{
+		network:  network,
+		addr:     addr,
+		tag:      printableASCII(tag, 48),
+		hostname: printableASCII(hostname, 255),
+		levels:   logrus.AllLevels,
+		msgs:     make(chan []byte, syslogBacklog),
+	}
+	if len(levels) > 0 {
+		h.levels = make([]logrus.Level, len(levels))
+		for i, level := range levels {
+			h.levels[i] = logrus.Level(level)
+		}
+	}
+	go h.run(conn)
+	AddHook(h)
+	return nil
+}
+
+type syslogHook struct {
+	network  string
+	addr     string
+	tag      string
+	hostname string
+	levels   []logrus.Level
+
+	msgs    chan []byte
+	dropped uint64
+}
+
+// Levels implements logrus.Hook.
+func (h *syslogHook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire implements logrus.Hook.
+func (h *syslogHook) Fire(entry *logrus.Entry) error {
+	msg := h.format(entry)
+	if !strings.HasPrefix(h.network, "udp") {
+		msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+	}
+
+	select {
+	case h.msgs <- msg:
+	default:
+		atomic.AddUint64(&h.dropped, 1)
+	}
+	return nil
+}
+
+// run writes queued messages to conn, re-dialing it after a failure.
+func (h *syslogHook) run(conn net.Conn) {
+	var retryAt time.Time
+	attempt := 0
+
+	for msg := range h.msgs {
+		if conn == nil {
+			if time.Now().Before(retryAt) {
+				atomic.AddUint64(&h.dropped, 1)
+				continue
+			}
+			c, err := net.DialTimeout(h.network, h.addr, syslogTimeout)
+			if err != nil {
+				retryAt = time.Now().Add(random.Backoff(time.Second, attempt, time.Minute, 0.5))
+				attempt++
+				atomic.AddUint64(&h.dropped, 1)
+				continue
+			}
+			conn, attempt = c, 0
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+		if _, err := conn.Write(msg); err != nil {
+			atomic.AddUint64(&h.dropped, 1)
+			conn.Close()
+			conn = nil
+		}
+	}
+}
+
+// format renders entry as an RFC 5424 message:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+func (h *syslogHook) format(entry *logrus.Entry) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %d - ",
+		syslogFacility*8+Level(entry.Level).Severity(),
+		entry.Time.Format("2006-01-02T15:04:05.000000Z07:00"),
+		h.hostname, h.tag, os.Getpid())
+
+	if len(entry.Data) == 0 {
+		b.WriteString("-")
+	} else {
+		keys := make([]string, 0, len(entry.Data))
+		for k := range entry.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		b.WriteString("[" + syslogSDID)
+		for _, k := range keys {
+			fmt.Fprintf(&b, ` %s="%s"`, sdName(k), syslogEscaper.Replace(fmt.Sprint(entry.Data[k])))
+		}
+		b.WriteString("]")
+	}
+
+	b.WriteString(" ")
+	b.WriteString(strings.TrimRight(entry.Message, "\n"))
+	return b.Bytes()
+}
+
+// sdName maps a field name to a valid SD-PARAM name: at most 32 printable
+// ASCII characters other than '=', ']' and '"'.
+func sdName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, printableASCII(name, 32))
+}
+
+// printableASCII truncates s to max bytes and replaces the characters that
+// are not printable ASCII, including space, with '_'. It returns the NILVALUE
+// "-" for an empty s, as RFC 5424 header fields may not be empty.
+func printableASCII(s string, max int) string {
+	if s == "" {
+		return "-"
+	}
+	b := []byte(s)
+	if len(b) > max {
+		b = b[:max]
+	}
+	for i, c := range b {
+		if c <= ' ' || c > '~' {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,74 @@
+package log
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddSyslogWriter(t *testing.T) {
+	defer origLogger.ReplaceHooks(make(logrus.LevelHooks))
+	defer SetOut(os.Stderr)
+	SetOut(ioutil.Discard)
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer pc.Close()
+
+	assert.NoError(t, AddSyslogWriter("udp", pc.LocalAddr().String(), "my app", []Level{ErrorLevel, WarnLevel}))
+
+	Info("not forwarded")
+	With("user", `a "quoted" ]name`).Error("disk full")
+
+	buf := make([]byte, 2048)
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	assert.NoError(t, err)
+
+	msg := string(buf[:n])
+	// facility user (1) * 8 + severity error (3) = 11
+	pattern := `^<11>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}(Z|[+-]\d\d:\d\d) \S+ my_app \d+ - ` +
+		`\[fields@32473 src="syslog_test\.go:\d+" user="a \\"quoted\\" \\]name"\] disk full$`
+	assert.Regexp(t, regexp.MustCompile(pattern), msg)
+
+	// local logging keeps working once the collector is gone
+	pc.Close()
+	assert.NotPanics(t, func() { Error("collector down") })
+
+	assert.Error(t, AddSyslogWriter("tcp", "127.0.0.1:1", "myapp", nil))
+}
+
+func TestSyslogWriterUnreachable(t *testing.T) {
+	defer origLogger.ReplaceHooks(make(logrus.LevelHooks))
+	defer SetOut(os.Stderr)
+	SetOut(ioutil.Discard)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	assert.NoError(t, AddSyslogWriter("tcp", ln.Addr().String(), "myapp", nil))
+	h := origLogger.Hooks[logrus.ErrorLevel][0].(*syslogHook)
+
+	// the collector goes away: logging must neither block nor fail
+	ln.Close()
+	start := time.Now()
+	for i := 0; i < 20000; i++ {
+		Error("collector down")
+	}
+	assert.True(t, time.Since(start) < 2*time.Second, "logging blocked for %s", time.Since(start))
+	assert.Eventually(t, func() bool { return atomic.LoadUint64(&h.dropped) > 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestPrintableASCII(t *testing.T) {
+	assert.Equal(t, "-", printableASCII("", 48))
+	assert.Equal(t, "my_app___", printableASCII("my app\té", 48))
+	assert.Len(t, printableASCII(strings.Repeat("a", 100), 48), 48)
+	assert.Equal(t, "a_b", sdName("a=b"))
+}
@@ -0,0 +1,30 @@
+package random
+
+import "time"
+
+// Backoff returns the delay before retry number attempt (starting at 0):
+// base * 2^attempt capped at max, reduced by a random share of up to
+// jitter. A jitter of 0 gives the plain exponential delay, 1 gives "full
+// jitter" in [0, delay] and values in between give [delay*(1-jitter), delay].
+// It panics if attempt is negative or jitter is outside [0, 1].
+func (r *Random) Backoff(base time.Duration, attempt int, max time.Duration, jitter float64) time.Duration {
+	if attempt < 0 {
+		panic("random: invalid argument to Backoff: negative attempt")
+	}
+	if !(jitter >= 0 && jitter <= 1) {
+		panic("random: invalid argument to Backoff: jitter outside [0, 1]")
+	}
+
+	d := max
+	if attempt < 63 && base <= max>>uint(attempt) {
+		d = base << uint(attempt)
+	}
+	if jitter == 0 {
+		return d
+	}
+	return d - time.Duration(r.float64()*jitter*float64(d))
+}
+
+func Backoff(base time.Duration, attempt int, max time.Duration, jitter float64) time.Duration {
+	return global.Backoff(base, attempt, max, jitter)
+}
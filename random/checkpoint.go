@@ -0,0 +1,99 @@
+package random
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+)
+
+var ErrInvalidCheckpoint = errors.New("random: invalid checkpoint")
+
+const (
+	checkpointVersion = 1
+	// maxCheckpointDraws bounds the replay done by NewFromCheckpoint, which
+	// takes about a second at this many draws.
+	maxCheckpointDraws = 1 << 28
+)
+
+// countingSource wraps a seeded math/rand source and counts the values
+// drawn from it, so its state can be rebuilt by replaying the draws.
+type countingSource struct {
+	src   rand.Source64
+	seed  int64
+	draws uint64
+}
+
+// newCountingSource returns a source seeded with seed that has already
+// produced draws values.
+func newCountingSource(seed int64, draws uint64) *countingSource {
+	s := &countingSource{src: rand.NewSource(seed).(rand.Source64), seed: seed}
+	for ; s.draws < draws; s.draws++ {
+		s.src.Uint64()
+	}
+	return s
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.seed = seed
+	s.draws = 0
+}
+
+// Checkpoint returns an opaque token capturing the current state of the
+// generator. NewFromCheckpoint restores it, so that a failed run can be
+// replayed from that point exactly.
+//
+// Only generators backed by math/rand, created with New or NewWithSeed,
+// can be checkpointed, and only until they have drawn 2^28 values from
+// their source; otherwise the token is empty.
+func (r *Random) Checkpoint() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.source()
+	if r.seeded == nil || r.seeded.draws > maxCheckpointDraws {
+		return ""
+	}
+
+	var b [17]byte
+	b[0] = checkpointVersion
+	binary.BigEndian.PutUint64(b[1:], uint64(r.seeded.seed))
+	binary.BigEndian.PutUint64(b[9:], r.seeded.draws)
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+func Checkpoint() string {
+	return global.Checkpoint()
+}
+
+// NewFromCheckpoint returns a generator in the state captured by token,
+// which produces the same sequence the checkpointed generator produced
+// after Checkpoint was called. Restoring replays every value drawn before
+// the checkpoint, so it takes time proportional to their number; tokens
+// claiming more than 2^28 draws, which Checkpoint never produces, are
+// rejected with ErrInvalidCheckpoint.
+func NewFromCheckpoint(token string) (*Random, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) != 17 || b[0] != checkpointVersion {
+		return nil, ErrInvalidCheckpoint
+	}
+
+	seed := int64(binary.BigEndian.Uint64(b[1:]))
+	draws := binary.BigEndian.Uint64(b[9:])
+	if draws > maxCheckpointDraws {
+		return nil, ErrInvalidCheckpoint
+	}
+	src := newCountingSource(seed, draws)
+	return &Random{rnd: rand.New(src), seeded: src}, nil
+}
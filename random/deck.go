@@ -0,0 +1,58 @@
+package random
+
+import "fmt"
+
+// Card is a playing card of a standard 52-card deck.
+// Rank runs from 1 (ace) to 13 (king).
+type Card struct {
+	Suit Suit
+	Rank int
+}
+
+// Suit is the suit of a Card.
+type Suit uint8
+
+// Suits
+const (
+	Clubs Suit = iota
+	Diamonds
+	Hearts
+	Spades
+)
+
+var (
+	suitNames = [...]string{"C", "D", "H", "S"}
+	rankNames = [...]string{"", "A", "2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K"}
+)
+
+func (s Suit) String() string {
+	if int(s) < len(suitNames) {
+		return suitNames[s]
+	}
+	return fmt.Sprintf("Suit(%d)", s)
+}
+
+func (c Card) String() string {
+	if c.Rank < 1 || c.Rank >= len(rankNames) {
+		return fmt.Sprintf("%d%s", c.Rank, c.Suit)
+	}
+	return rankNames[c.Rank] + c.Suit.String()
+}
+
+// Deck returns a freshly shuffled 52-card deck.
+func (r *Random) Deck() []Card {
+	deck := make([]Card, 0, 52)
+	for s := Clubs; s <= Spades; s++ {
+		for rank := 1; rank <= 13; rank++ {
+			deck = append(deck, Card{Suit: s, Rank: rank})
+		}
+	}
+	r.shuffle(len(deck), func(i, j int) {
+		deck[i], deck[j] = deck[j], deck[i]
+	})
+	return deck
+}
+
+func Deck() []Card {
+	return global.Deck()
+}
@@ -0,0 +1,45 @@
+package random
+
+import "errors"
+
+var (
+	ErrInvalidRange  = errors.New("random: invalid range or count")
+	ErrRangeTooSmall = errors.New("random: range has fewer values than count")
+)
+
+// DistinctInts returns count distinct integers sampled uniformly from
+// [min, max], in random order. It runs a partial Fisher-Yates shuffle over
+// a sparse view of the range, so memory is proportional to count rather
+// than to the size of the range.
+func (r *Random) DistinctInts(min, max, count int) ([]int, error) {
+	span := int64(max) - int64(min) + 1
+	// span overflows when the range is wider than math.MaxInt64
+	if min > max || count < 0 || span <= 0 {
+		return nil, ErrInvalidRange
+	}
+	if span < int64(count) {
+		return nil, ErrRangeTooSmall
+	}
+
+	// swapped holds the offsets whose slot no longer contains itself.
+	swapped := make(map[int64]int64, count)
+	at := func(i int64) int64 {
+		if v, ok := swapped[i]; ok {
+			return v
+		}
+		return i
+	}
+
+	out := make([]int, count)
+	for i := range out {
+		j := int64(i) + r.int63n(span-int64(i))
+		vi, vj := at(int64(i)), at(j)
+		swapped[j] = vi
+		out[i] = min + int(vj)
+	}
+	return out, nil
+}
+
+func DistinctInts(min, max, count int) ([]int, error) {
+	return global.DistinctInts(min, max, count)
+}
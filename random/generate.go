@@ -0,0 +1,38 @@
+package random
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	"github.com/srelab/common/file"
+)
+
+var ErrInvalidCount = errors.New("random: negative count")
+
+// GenerateToFile writes count values produced by gen, one per line, to the
+// file at path. Values are streamed to disk as they are generated, and the
+// file is replaced atomically once all of them have been written.
+// A negative count returns ErrInvalidCount and leaves path untouched.
+func (r *Random) GenerateToFile(path string, count int, gen func(*Random) string) error {
+	if count < 0 {
+		return ErrInvalidCount
+	}
+
+	return file.WriteAtomicFunc(path, 0644, func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		for i := 0; i < count; i++ {
+			if _, err := bw.WriteString(gen(r)); err != nil {
+				return err
+			}
+			if err := bw.WriteByte('\n'); err != nil {
+				return err
+			}
+		}
+		return bw.Flush()
+	})
+}
+
+func GenerateToFile(path string, count int, gen func(*Random) string) error {
+	return global.GenerateToFile(path, count, gen)
+}
@@ -0,0 +1,26 @@
+package random
+
+// BoolGrid returns a rows x cols grid in which each cell is independently
+// true with probability trueProb. Like math/rand, it panics on invalid
+// arguments: negative dimensions or trueProb outside [0, 1].
+func (r *Random) BoolGrid(rows, cols int, trueProb float64) [][]bool {
+	if rows < 0 || cols < 0 {
+		panic("random: invalid argument to BoolGrid: negative dimension")
+	}
+	if !(trueProb >= 0 && trueProb <= 1) {
+		panic("random: invalid argument to BoolGrid: probability outside [0, 1]")
+	}
+
+	grid := make([][]bool, rows)
+	for i := range grid {
+		grid[i] = make([]bool, cols)
+		for j := range grid[i] {
+			grid[i][j] = r.float64() < trueProb
+		}
+	}
+	return grid
+}
+
+func BoolGrid(rows, cols int, trueProb float64) [][]bool {
+	return global.BoolGrid(rows, cols, trueProb)
+}
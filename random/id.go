@@ -0,0 +1,26 @@
+package random
+
+import (
+	"fmt"
+	"time"
+)
+
+// OrderedID returns a 24-character ID made of a 16-digit hex nanosecond
+// timestamp followed by 8 random alphanumeric characters. IDs from the same
+// generator sort lexicographically in creation order: when the clock has
+// not advanced, the timestamp is bumped by one nanosecond.
+func (r *Random) OrderedID() string {
+	r.mu.Lock()
+	ts := time.Now().UnixNano()
+	if ts <= r.lastID {
+		ts = r.lastID + 1
+	}
+	r.lastID = ts
+	r.mu.Unlock()
+
+	return fmt.Sprintf("%016x", ts) + r.String(8, Alphanumeric)
+}
+
+func OrderedID() string {
+	return global.OrderedID()
+}
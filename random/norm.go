@@ -0,0 +1,32 @@
+package random
+
+import "math"
+
+// clampedNormAttempts bounds how often ClampedNorm resamples before clamping.
+const clampedNormAttempts = 16
+
+// ClampedNorm returns a normally distributed value with the given mean and
+// standard deviation that lies within [min, max]. Out-of-range draws are
+// resampled a few times and then clamped, so a range far from the mean
+// does not loop forever. It panics if min >= max or stddev is negative.
+func (r *Random) ClampedNorm(mean, stddev, min, max float64) float64 {
+	if !(min < max) {
+		panic("random: invalid argument to ClampedNorm: min >= max")
+	}
+	if !(stddev >= 0) {
+		panic("random: invalid argument to ClampedNorm: negative stddev")
+	}
+
+	var v float64
+	for i := 0; i < clampedNormAttempts; i++ {
+		v = mean + stddev*r.normFloat64()
+		if v >= min && v <= max {
+			return v
+		}
+	}
+	return math.Max(min, math.Min(max, v))
+}
+
+func ClampedNorm(mean, stddev, min, max float64) float64 {
+	return global.ClampedNorm(mean, stddev, min, max)
+}
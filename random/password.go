@@ -0,0 +1,71 @@
+package random
+
+import (
+	"errors"
+)
+
+var (
+	ErrPolicyTooLong   = errors.New("random: required characters exceed the requested length")
+	ErrEmptyCharClass  = errors.New("random: character class has an empty charset")
+	ErrNegativeMinimum = errors.New("random: character class has a negative minimum")
+)
+
+// CharClass requires at least Min characters from Charset,
+// e.g. CharClass{Charset: Numeric, Min: 1}.
+type CharClass struct {
+	Charset string
+	Min     int
+}
+
+// Password returns a string of length characters satisfying every class
+// minimum. Remaining characters are drawn from the union of all classes and
+// the result is shuffled, so required characters have no fixed position.
+// It fails with ErrPolicyTooLong if the minimums add up to more than length,
+// with ErrInvalidLength if length is negative, and with the error reported
+// by Err if a generator created by NewSecure fell back to math/rand.
+func (r *Random) Password(length int, classes ...CharClass) (string, error) {
+	if length < 0 {
+		return "", ErrInvalidLength
+	}
+	if len(classes) == 0 {
+		return r.secured(r.StringFrom(length, ""))
+	}
+
+	required := 0
+	var union []rune
+	for _, c := range classes {
+		if c.Charset == "" {
+			return "", ErrEmptyCharClass
+		}
+		if c.Min < 0 {
+			return "", ErrNegativeMinimum
+		}
+		required += c.Min
+		union = append(union, []rune(c.Charset)...)
+	}
+	if required > length {
+		return "", ErrPolicyTooLong
+	}
+
+	b := make([]rune, 0, length)
+	for _, c := range classes {
+		b = append(b, r.runes(c.Min, []rune(c.Charset))...)
+	}
+	b = append(b, r.runes(length-required, union)...)
+	r.shuffle(len(b), func(i, j int) {
+		b[i], b[j] = b[j], b[i]
+	})
+	return r.secured(string(b))
+}
+
+// secured withholds a secret generated after a secure source fell back.
+func (r *Random) secured(s string) (string, error) {
+	if err := r.Err(); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+func Password(length int, classes ...CharClass) (string, error) {
+	return global.Password(length, classes...)
+}
@@ -3,11 +3,17 @@ package random
 import (
 	"math/rand"
 	"strings"
+	"sync"
 	"time"
 )
 
 type (
 	Random struct {
+		mu     sync.Mutex
+		rnd    *rand.Rand
+		secure *secureSource
+		seeded *countingSource
+		lastID int64
 	}
 )
 
@@ -26,23 +32,91 @@ var (
 	global = New()
 )
 
+// New returns a fast generator backed by math/rand. Its output is
+// predictable and must not be used for tokens, passwords or other secrets;
+// use NewSecure for those.
 func New() *Random {
-	rand.Seed(time.Now().UnixNano())
-	return new(Random)
+	return NewWithSeed(time.Now().UnixNano())
+}
+
+// NewWithSeed returns a generator whose output is fully determined by seed,
+// which makes it suitable for reproducible tests.
+func NewWithSeed(seed int64) *Random {
+	src := newCountingSource(seed, 0)
+	return &Random{rnd: rand.New(src), seeded: src}
+}
+
+// source returns the generator's math/rand source, seeding it from the
+// clock on first use so that the zero Random is ready to use like New().
+// It must be called with r.mu held.
+func (r *Random) source() *rand.Rand {
+	if r.rnd == nil {
+		r.seeded = newCountingSource(time.Now().UnixNano(), 0)
+		r.rnd = rand.New(r.seeded)
+	}
+	return r.rnd
+}
+
+func (r *Random) intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.source().Intn(n)
+}
+
+func (r *Random) int63n(n int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.source().Int63n(n)
+}
+
+func (r *Random) float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.source().Float64()
+}
+
+func (r *Random) normFloat64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.source().NormFloat64()
+}
+
+func (r *Random) shuffle(n int, swap func(i, j int)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.source().Shuffle(n, swap)
 }
 
 func (r *Random) String(length uint8, charsets ...string) string {
-	charset := strings.Join(charsets, "")
+	return r.StringFrom(int(length), strings.Join(charsets, ""))
+}
+
+func String(length uint8, charsets ...string) string {
+	return global.String(length, charsets...)
+}
+
+// StringFrom returns a string of length characters drawn uniformly from
+// the caller-supplied alphabet, which may contain any runes.
+// An empty charset falls back to Alphanumeric.
+// A negative length gives an empty string.
+func (r *Random) StringFrom(length int, charset string) string {
 	if charset == "" {
 		charset = Alphanumeric
 	}
-	b := make([]byte, length)
-	for i := range b {
-		b[i] = charset[rand.Int63()%int64(len(charset))]
-	}
-	return string(b)
+	return string(r.runes(length, []rune(charset)))
 }
 
-func String(length uint8, charsets ...string) string {
-	return global.String(length, charsets...)
+func StringFrom(length int, charset string) string {
+	return global.StringFrom(length, charset)
+}
+
+func (r *Random) runes(length int, alphabet []rune) []rune {
+	if length < 0 {
+		length = 0
+	}
+	b := make([]rune, length)
+	for i := range b {
+		b[i] = alphabet[r.intn(len(alphabet))]
+	}
+	return b
 }
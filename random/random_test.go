@@ -1,8 +1,20 @@
 package random
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -12,3 +24,384 @@ func Test(t *testing.T) {
 	r := New()
 	assert.Regexp(t, regexp.MustCompile("[0-9]+$"), r.String(8, Numeric))
 }
+
+func TestDeck(t *testing.T) {
+	deck := New().Deck()
+	assert.Len(t, deck, 52)
+
+	seen := make(map[Card]bool)
+	for _, c := range deck {
+		assert.True(t, c.Rank >= 1 && c.Rank <= 13)
+		assert.True(t, c.Suit <= Spades)
+		seen[c] = true
+	}
+	assert.Len(t, seen, 52)
+
+	assert.Equal(t, NewWithSeed(42).Deck(), NewWithSeed(42).Deck())
+	assert.NotEqual(t, NewWithSeed(42).Deck(), NewWithSeed(43).Deck())
+}
+
+func TestUserAgent(t *testing.T) {
+	ua := regexp.MustCompile(`^Mozilla/5\.0 \([^()]+\) [A-Za-z]+/[\d.]+( \([^()]+\))?( [A-Za-z]+/[\d.]+)+$`)
+	r := New()
+	for i := 0; i < 100; i++ {
+		assert.Regexp(t, ua, r.UserAgent())
+	}
+	assert.Equal(t, NewWithSeed(7).UserAgent(), NewWithSeed(7).UserAgent())
+}
+
+func TestStream(t *testing.T) {
+	out := make(chan string)
+	go func() {
+		assert.NoError(t, New().Stream(context.Background(), 10, out, 8))
+	}()
+	count := 0
+	for s := range out {
+		assert.Len(t, s, 8)
+		count++
+	}
+	assert.Equal(t, 10, count)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out = make(chan string)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- New().Stream(ctx, 1000, out, 8)
+	}()
+	count = 0
+	for range out {
+		count++
+		if count == 5 {
+			cancel()
+		}
+	}
+	assert.Equal(t, context.Canceled, <-errc)
+	assert.True(t, count < 1000)
+
+	out = make(chan string)
+	go func() {
+		assert.NoError(t, New().Stream(context.Background(), 3, out, 300, Hex))
+	}()
+	for s := range out {
+		assert.Regexp(t, `^[0-9a-f]{300}$`, s)
+	}
+
+	out = make(chan string)
+	assert.Equal(t, ErrInvalidLength, New().Stream(context.Background(), 3, out, -1))
+	_, open := <-out
+	assert.False(t, open)
+}
+
+func TestZeroRandom(t *testing.T) {
+	var r Random
+	assert.Len(t, r.String(8), 8)
+	assert.Len(t, new(Random).StringFrom(300, Hex), 300)
+	assert.Len(t, r.Deck(), 52)
+	assert.NotEmpty(t, r.Checkpoint())
+	assert.NoError(t, r.Err())
+}
+
+func TestNewSecure(t *testing.T) {
+	r := NewSecure()
+	assert.Len(t, r.String(32), 32)
+	assert.Regexp(t, regexp.MustCompile("^[0-9a-f]{16}$"), r.String(16, Hex))
+	assert.NoError(t, r.Err())
+	assert.NotEqual(t, r.String(32), r.String(32))
+
+	// every symbol of a charset whose size does not divide 2^63
+	// should be drawn about equally often.
+	const charset = "abc"
+	counts := make(map[rune]int)
+	for _, c := range r.String(255, charset) + r.String(255, charset) + r.String(255, charset) + r.String(255, charset) {
+		counts[c]++
+	}
+	assert.Len(t, counts, 3)
+	for _, n := range counts {
+		assert.InDelta(t, 340, n, 100)
+	}
+}
+
+func TestStringFrom(t *testing.T) {
+	assert.Regexp(t, regexp.MustCompile("^[xyz]{20}$"), StringFrom(20, "xyz"))
+	assert.Regexp(t, regexp.MustCompile("^[甲乙丙]{5}$"), New().StringFrom(5, "甲乙丙"))
+	assert.Len(t, StringFrom(12, ""), 12)
+}
+
+func TestPassword(t *testing.T) {
+	policy := []CharClass{
+		{Charset: Uppercase, Min: 1},
+		{Charset: Numeric, Min: 2},
+		{Charset: Symbols, Min: 1},
+		{Charset: Lowercase},
+	}
+	counts := func(s, charset string) int {
+		n := 0
+		for _, c := range s {
+			if strings.ContainsRune(charset, c) {
+				n++
+			}
+		}
+		return n
+	}
+
+	r := New()
+	firstDigit := make(map[int]bool)
+	for i := 0; i < 1000; i++ {
+		p, err := r.Password(8, policy...)
+		assert.NoError(t, err)
+		assert.Len(t, p, 8)
+		assert.True(t, counts(p, Uppercase) >= 1, p)
+		assert.True(t, counts(p, Numeric) >= 2, p)
+		assert.True(t, counts(p, Symbols) >= 1, p)
+		firstDigit[strings.IndexAny(p, Numeric)] = true
+	}
+	assert.True(t, len(firstDigit) > 1, "required characters are always in the same position")
+
+	_, err := Password(3, policy...)
+	assert.Equal(t, ErrPolicyTooLong, err)
+	_, err = Password(8, CharClass{Min: 1})
+	assert.Equal(t, ErrEmptyCharClass, err)
+	for _, classes := range [][]CharClass{nil, policy} {
+		_, err = Password(-1, classes...)
+		assert.Equal(t, ErrInvalidLength, err)
+	}
+	assert.Empty(t, StringFrom(-1, Hex))
+
+	// a secure generator that fell back to math/rand refuses to make secrets
+	failed := errors.New("entropy unavailable")
+	src := &secureSource{err: failed, fallback: rand.NewSource(1).(rand.Source64)}
+	fallback := &Random{rnd: rand.New(src), secure: src}
+	for _, classes := range [][]CharClass{nil, policy} {
+		p, err := fallback.Password(12, classes...)
+		assert.Equal(t, failed, err)
+		assert.Empty(t, p)
+	}
+}
+
+func TestOrderedID(t *testing.T) {
+	r := New()
+	ids := make([]string, 10000)
+	for i := range ids {
+		ids[i] = r.OrderedID()
+		assert.Len(t, ids[i], 24)
+	}
+	assert.True(t, sort.StringsAreSorted(ids))
+
+	time.Sleep(time.Millisecond)
+	assert.True(t, OrderedID() > ids[len(ids)-1])
+}
+
+func TestBoolGrid(t *testing.T) {
+	r := NewWithSeed(1)
+	for _, p := range []float64{0, 0.1, 0.5, 0.9, 1} {
+		grid := r.BoolGrid(200, 300, p)
+		assert.Len(t, grid, 200)
+
+		trues := 0
+		for _, row := range grid {
+			assert.Len(t, row, 300)
+			for _, cell := range row {
+				if cell {
+					trues++
+				}
+			}
+		}
+		assert.InDelta(t, p, float64(trues)/(200*300), 0.01)
+	}
+
+	assert.Len(t, BoolGrid(0, 5, 0.5), 0)
+	assert.Panics(t, func() { BoolGrid(-1, 5, 0.5) })
+	assert.Panics(t, func() { BoolGrid(5, 5, 1.5) })
+	assert.Panics(t, func() { BoolGrid(5, 5, math.NaN()) })
+}
+
+func TestClampedNorm(t *testing.T) {
+	r := NewWithSeed(1)
+	const n = 100000
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		v := r.ClampedNorm(200, 50, 10, 390)
+		assert.True(t, v >= 10 && v <= 390, "%v out of range", v)
+		sum += v
+	}
+	assert.InDelta(t, 200, sum/n, 1)
+
+	for i := 0; i < 1000; i++ {
+		v := ClampedNorm(0, 1, 100, 101)
+		assert.True(t, v >= 100 && v <= 101, "%v out of range", v)
+	}
+
+	assert.Panics(t, func() { ClampedNorm(0, 1, 5, 5) })
+	assert.Panics(t, func() { ClampedNorm(0, -1, 0, 5) })
+}
+
+func TestDistinctInts(t *testing.T) {
+	r := NewWithSeed(1)
+	for _, tt := range []struct{ min, max, count int }{
+		{0, 9, 10},
+		{-5, 5, 3},
+		{1, 1 << 40, 1000},
+		{7, 7, 1},
+		{3, 8, 0},
+	} {
+		ints, err := r.DistinctInts(tt.min, tt.max, tt.count)
+		assert.NoError(t, err)
+		assert.Len(t, ints, tt.count)
+
+		seen := make(map[int]bool)
+		for _, v := range ints {
+			assert.True(t, v >= tt.min && v <= tt.max, "%d out of [%d, %d]", v, tt.min, tt.max)
+			assert.False(t, seen[v], "%d repeated", v)
+			seen[v] = true
+		}
+	}
+
+	// each value of a small range should be picked about equally often
+	counts := make(map[int]int)
+	for i := 0; i < 10000; i++ {
+		ints, _ := r.DistinctInts(1, 10, 2)
+		counts[ints[0]]++
+		counts[ints[1]]++
+	}
+	for v := 1; v <= 10; v++ {
+		assert.InDelta(t, 2000, counts[v], 200, "value %d", v)
+	}
+
+	_, err := DistinctInts(1, 5, 6)
+	assert.Equal(t, ErrRangeTooSmall, err)
+	_, err = DistinctInts(5, 1, 1)
+	assert.Equal(t, ErrInvalidRange, err)
+}
+
+func TestUUIDv5(t *testing.T) {
+	// reference values from Python's uuid.uuid5
+	assert.Equal(t, "2ed6657d-e927-568b-95e1-2665a8aea6a2", UUIDv5(NamespaceDNS, "www.example.com"))
+	assert.Equal(t, UUIDv5(NamespaceURL, "https://example.com/"), UUIDv5(NamespaceURL, "https://example.com/"))
+	assert.NotEqual(t, UUIDv5(NamespaceDNS, "example.com"), UUIDv5(NamespaceURL, "example.com"))
+
+	u := UUIDv5(NamespaceDNS, "srelab")
+	assert.Regexp(t, regexp.MustCompile("^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$"), u)
+}
+
+func TestBackoff(t *testing.T) {
+	base, max := 100*time.Millisecond, 10*time.Second
+	assert.Equal(t, 100*time.Millisecond, Backoff(base, 0, max, 0))
+	assert.Equal(t, 800*time.Millisecond, Backoff(base, 3, max, 0))
+	assert.Equal(t, max, Backoff(base, 20, max, 0))
+	assert.Equal(t, max, Backoff(base, 200, max, 0))
+
+	r := New()
+	for attempt := 0; attempt < 10; attempt++ {
+		for _, jitter := range []float64{0.25, 1} {
+			d := base << uint(attempt)
+			if d > max {
+				d = max
+			}
+			lower := time.Duration(float64(d) * (1 - jitter))
+			for i := 0; i < 100; i++ {
+				got := r.Backoff(base, attempt, max, jitter)
+				assert.True(t, got >= lower && got <= d, "attempt %d jitter %v: %v not in [%v, %v]", attempt, jitter, got, lower, d)
+			}
+		}
+	}
+
+	assert.Panics(t, func() { Backoff(base, -1, max, 0) })
+	assert.Panics(t, func() { Backoff(base, 1, max, 1.5) })
+}
+
+func TestWeightedMapChoice(t *testing.T) {
+	weights := map[string]int{"a": 1, "b": 3, "c": 6, "never": 0}
+
+	r := NewWithSeed(1)
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		k, err := WeightedMapChoice(r, weights)
+		assert.NoError(t, err)
+		counts[k]++
+	}
+	assert.InDelta(t, 1000, counts["a"], 150)
+	assert.InDelta(t, 3000, counts["b"], 250)
+	assert.InDelta(t, 6000, counts["c"], 250)
+	assert.Zero(t, counts["never"])
+
+	// the same seed picks the same keys despite map iteration order
+	a, b := NewWithSeed(42), NewWithSeed(42)
+	for i := 0; i < 100; i++ {
+		ka, _ := WeightedMapChoice(a, weights)
+		kb, _ := WeightedMapChoice(b, map[string]int{"c": 6, "b": 3, "a": 1})
+		assert.Equal(t, ka, kb)
+	}
+
+	_, err := WeightedMapChoice(nil, map[int]int{1: 2})
+	assert.NoError(t, err)
+	for _, bad := range []map[int]int{nil, {1: 0}, {1: 5, 2: -1}, {1: math.MaxInt64, 2: 1}} {
+		_, err := WeightedMapChoice(r, bad)
+		assert.Equal(t, ErrInvalidWeights, err, "%v", bad)
+	}
+}
+
+func TestGenerateToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test_generate_to_file")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "ids.txt")
+	r := NewWithSeed(1)
+	assert.NoError(t, r.GenerateToFile(path, 1000, func(r *Random) string {
+		return r.StringFrom(12, Numeric)
+	}))
+
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	assert.Len(t, lines, 1000)
+	for _, line := range lines {
+		assert.Regexp(t, `^\d{12}$`, line)
+	}
+
+	assert.Equal(t, ErrInvalidCount, GenerateToFile(path, -1, nil))
+	b, _ = ioutil.ReadFile(path)
+	assert.Len(t, b, 1000*13)
+
+	assert.NoError(t, GenerateToFile(path, 0, nil))
+	b, _ = ioutil.ReadFile(path)
+	assert.Empty(t, b)
+}
+
+func TestCheckpoint(t *testing.T) {
+	r := NewWithSeed(7)
+	for i := 0; i < 100; i++ {
+		r.String(16, Alphanumeric)
+		r.ClampedNorm(0, 1, -2, 2)
+	}
+	r.Deck()
+
+	token := r.Checkpoint()
+	restored, err := NewFromCheckpoint(token)
+	assert.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, r.String(16, Alphanumeric), restored.String(16, Alphanumeric))
+		assert.Equal(t, r.ClampedNorm(0, 1, -2, 2), restored.ClampedNorm(0, 1, -2, 2))
+		assert.Equal(t, r.Deck(), restored.Deck())
+	}
+	assert.Equal(t, r.Checkpoint(), restored.Checkpoint())
+	assert.NotEqual(t, token, r.Checkpoint())
+
+	assert.NotEmpty(t, Checkpoint())
+	assert.Empty(t, NewSecure().Checkpoint())
+	forged := make([]byte, 17)
+	forged[0] = checkpointVersion
+	binary.BigEndian.PutUint64(forged[9:], 1<<40)
+	for _, bad := range []string{
+		"",
+		"not base64!",
+		token[:10],
+		NewWithSeed(1).Checkpoint() + "AA",
+		base64.RawURLEncoding.EncodeToString(forged),
+	} {
+		_, err := NewFromCheckpoint(bad)
+		assert.Equal(t, ErrInvalidCheckpoint, err, bad)
+	}
+}
@@ -0,0 +1,66 @@
+package random
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// secureSource is a rand.Source64 reading from crypto/rand. If crypto/rand
+// fails it records the error and switches to a time-seeded math/rand source
+// rather than panicking.
+type secureSource struct {
+	mu       sync.Mutex
+	err      error
+	fallback rand.Source64
+}
+
+func (s *secureSource) Uint64() uint64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.err == nil {
+			s.err = err
+			s.fallback = rand.NewSource(time.Now().UnixNano()).(rand.Source64)
+		}
+		return s.fallback.Uint64()
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+func (s *secureSource) Int63() int64 {
+	return int64(s.Uint64() & (1<<63 - 1))
+}
+
+// Seed is a no-op; a secure source cannot be seeded.
+func (s *secureSource) Seed(int64) {}
+
+func (s *secureSource) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// NewSecure returns a generator backed by crypto/rand, suitable for tokens,
+// temporary passwords and other secrets. It has the same API as New, and
+// values are drawn without modulo bias.
+//
+// Should crypto/rand ever fail, the generator keeps working on a math/rand
+// fallback instead of panicking; Err reports the failure so callers
+// generating secrets can reject the output.
+func NewSecure() *Random {
+	src := &secureSource{}
+	return &Random{rnd: rand.New(src), secure: src}
+}
+
+// Err returns the error that forced a generator created by NewSecure onto
+// its fallback source, or nil.
+func (r *Random) Err() error {
+	if r.secure == nil {
+		return nil
+	}
+	return r.secure.Err()
+}
@@ -0,0 +1,31 @@
+package random
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidLength = errors.New("random: negative length")
+
+// Stream sends n random strings of the given length, drawn from charsets,
+// on out and closes it when done. It stops early and returns ctx.Err()
+// if ctx is canceled, and returns ErrInvalidLength without sending
+// anything if length is negative.
+func (r *Random) Stream(ctx context.Context, n int, out chan<- string, length int, charsets ...string) error {
+	defer close(out)
+
+	if length < 0 {
+		return ErrInvalidLength
+	}
+
+	charset := strings.Join(charsets, "")
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case out <- r.StringFrom(length, charset):
+		}
+	}
+	return nil
+}
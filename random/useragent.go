@@ -0,0 +1,47 @@
+package random
+
+import "strings"
+
+// User-agent building blocks. Each template's {os} and {version}
+// placeholders are filled from the matching platform and version lists.
+var (
+	UserAgentPlatforms = []string{
+		"Windows NT 10.0; Win64; x64",
+		"Windows NT 6.1; Win64; x64",
+		"Macintosh; Intel Mac OS X 10_15_7",
+		"Macintosh; Intel Mac OS X 13_4",
+		"X11; Linux x86_64",
+		"X11; Ubuntu; Linux x86_64",
+	}
+
+	UserAgentTemplates = []struct {
+		Template string
+		Versions []string
+	}{
+		{
+			Template: "Mozilla/5.0 ({os}) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/{version} Safari/537.36",
+			Versions: []string{"114.0.5735.199", "118.0.5993.88", "120.0.6099.109", "124.0.6367.91"},
+		},
+		{
+			Template: "Mozilla/5.0 ({os}; rv:{version}) Gecko/20100101 Firefox/{version}",
+			Versions: []string{"109.0", "115.0", "121.0", "125.0"},
+		},
+		{
+			Template: "Mozilla/5.0 ({os}) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/{version} Safari/537.36 Edg/{version}",
+			Versions: []string{"114.0.1823.67", "120.0.2210.77", "124.0.2478.67"},
+		},
+	}
+)
+
+// UserAgent returns a plausible desktop browser user-agent string.
+func (r *Random) UserAgent() string {
+	t := UserAgentTemplates[r.intn(len(UserAgentTemplates))]
+	return strings.NewReplacer(
+		"{os}", UserAgentPlatforms[r.intn(len(UserAgentPlatforms))],
+		"{version}", t.Versions[r.intn(len(t.Versions))],
+	).Replace(t.Template)
+}
+
+func UserAgent() string {
+	return global.UserAgent()
+}
@@ -0,0 +1,49 @@
+package random
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var ErrInvalidWeights = errors.New("random: weights must be non-negative with a positive total")
+
+// WeightedMapChoice returns a key of weights chosen with probability
+// proportional to its weight. Keys with zero weight are never chosen.
+// Keys are visited in the order of their %#v representation, so the result
+// is reproducible for a generator created with NewWithSeed. A nil r uses
+// the package generator.
+func WeightedMapChoice[K comparable](r *Random, weights map[K]int) (K, error) {
+	var zero K
+	if r == nil {
+		r = global
+	}
+
+	keys := make([]K, 0, len(weights))
+	names := make(map[K]string, len(weights))
+	var total int64
+	for k, w := range weights {
+		if w < 0 || total > math.MaxInt64-int64(w) {
+			return zero, ErrInvalidWeights
+		}
+		if w == 0 {
+			continue
+		}
+		total += int64(w)
+		keys = append(keys, k)
+		names[k] = fmt.Sprintf("%#v", k)
+	}
+	if total <= 0 {
+		return zero, ErrInvalidWeights
+	}
+	sort.Slice(keys, func(i, j int) bool { return names[keys[i]] < names[keys[j]] })
+
+	n := r.int63n(total)
+	for _, k := range keys[:len(keys)-1] {
+		if n -= int64(weights[k]); n < 0 {
+			return k, nil
+		}
+	}
+	return keys[len(keys)-1], nil
+}
@@ -0,0 +1,46 @@
+package validator
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrExpiryMonthInvalid = errors.New("有效期月份错误")
+	ErrExpired            = errors.New("卡片已过期")
+	ErrCVVInvalid         = errors.New("安全码格式错误")
+
+	cvvReg = regexp.MustCompile("^\\d+$")
+)
+
+// ValidateExpiry 校验信用卡有效期, yy 可为两位或四位年份, 有效期截止到当月月底
+func ValidateExpiry(mm, yy int) (bool, error) {
+	if mm < 1 || mm > 12 {
+		return false, ErrExpiryMonthInvalid
+	}
+	if yy < 100 {
+		yy += 2000
+	}
+
+	now := time.Now()
+	if yy < now.Year() || (yy == now.Year() && mm < int(now.Month())) {
+		return false, ErrExpired
+	}
+	return true, nil
+}
+
+// ValidateCVV 校验安全码, American Express 为4位, 其余卡组织为3位
+func ValidateCVV(s string, brand string) (bool, error) {
+	length := 3
+	switch strings.ToLower(strings.TrimSpace(brand)) {
+	case "amex", "american express", "americanexpress":
+		length = 4
+	}
+
+	if len(s) != length || !cvvReg.MatchString(s) {
+		return false, ErrCVVInvalid
+	}
+	return true, nil
+}
@@ -0,0 +1,51 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateExpiry(t *testing.T) {
+	now := time.Now()
+	last := now.AddDate(0, -int(now.Month()), 0)
+
+	ok, err := ValidateExpiry(int(last.Month()), last.Year()%100)
+	assert.False(t, ok)
+	assert.Equal(t, ErrExpired, err)
+
+	ok, err = ValidateExpiry(int(now.Month()), now.Year())
+	assert.True(t, ok)
+	assert.NoError(t, err)
+
+	ok, err = ValidateExpiry(1, now.Year()%100+3)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+
+	_, err = ValidateExpiry(13, now.Year()+1)
+	assert.Equal(t, ErrExpiryMonthInvalid, err)
+}
+
+func TestValidateCVV(t *testing.T) {
+	tests := []struct {
+		cvv   string
+		brand string
+		ok    bool
+	}{
+		{"123", "visa", true},
+		{"1234", "visa", false},
+		{"1234", "amex", true},
+		{"123", "AMEX", false},
+		{"12a", "mastercard", false},
+		{"", "unionpay", false},
+	}
+
+	for _, tt := range tests {
+		ok, err := ValidateCVV(tt.cvv, tt.brand)
+		assert.Equal(t, tt.ok, ok, tt.cvv+"/"+tt.brand)
+		if !tt.ok {
+			assert.Equal(t, ErrCVVInvalid, err)
+		}
+	}
+}
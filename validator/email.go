@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/mail"
+	"strings"
+)
+
+var (
+	ErrEmailInvalid = errors.New("邮箱格式错误")
+	ErrEmailNoMX    = errors.New("邮箱域名无法接收邮件")
+
+	// Resolver 用于 ValidateEmailMX 的 MX 查询, 测试时可替换
+	Resolver MXResolver = net.DefaultResolver
+)
+
+// MXResolver 查询域名的 MX 记录, *net.Resolver 满足该接口
+type MXResolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+// Email 邮箱地址, 不接受带显示名的形式(如 "Name <a@b.com>")
+type Email struct {
+	Address string
+}
+
+// 校验
+func (e *Email) Validate() (bool, error) {
+	addr, err := mail.ParseAddress(e.Address)
+	if err != nil || addr.Address != e.Address {
+		return false, ErrEmailInvalid
+	}
+
+	at := strings.LastIndex(addr.Address, "@")
+	if domain := addr.Address[at+1:]; !strings.Contains(domain, ".") || strings.HasSuffix(domain, ".") {
+		return false, ErrEmailInvalid
+	}
+	return true, nil
+}
+
+// ValidateEmailMX 先校验邮箱格式, 再查询域名的 MX 记录确认其可以接收邮件
+// 查询遵循 ctx 的超时和取消; 域名不存在或没有 MX 记录时返回 ErrEmailNoMX
+func ValidateEmailMX(ctx context.Context, addr string) (bool, error) {
+	e := Email{Address: addr}
+	if ok, err := e.Validate(); !ok {
+		return false, err
+	}
+
+	domain := addr[strings.LastIndex(addr, "@")+1:]
+	mx, err := Resolver.LookupMX(ctx, domain)
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+			return false, ErrEmailNoMX
+		}
+		return false, err
+	}
+
+	for _, record := range mx {
+		// "." 为 RFC 7505 定义的 null MX, 表示域名不接收邮件
+		if record.Host != "." && record.Host != "" {
+			return true, nil
+		}
+	}
+	return false, ErrEmailNoMX
+}
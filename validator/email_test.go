@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeResolver map[string][]*net.MX
+
+func (f fakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if mx, ok := f[name]; ok {
+		return mx, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func TestValidateEmailMX(t *testing.T) {
+	defer func(r MXResolver) { Resolver = r }(Resolver)
+	Resolver = fakeResolver{
+		"example.com": {{Host: "mx.example.com.", Pref: 10}},
+		"null.test":   {{Host: ".", Pref: 0}},
+	}
+
+	ok, err := ValidateEmailMX(context.Background(), "ops@example.com")
+	assert.True(t, ok)
+	assert.NoError(t, err)
+
+	ok, err = ValidateEmailMX(context.Background(), "ops@nonexistent.invalid")
+	assert.False(t, ok)
+	assert.Equal(t, ErrEmailNoMX, err)
+
+	_, err = ValidateEmailMX(context.Background(), "ops@null.test")
+	assert.Equal(t, ErrEmailNoMX, err)
+
+	_, err = ValidateEmailMX(context.Background(), "not an email")
+	assert.Equal(t, ErrEmailInvalid, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ValidateEmailMX(ctx, "ops@example.com")
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,44 @@
+package validator
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrCoordinateFormatInvalid = errors.New("坐标格式错误")
+	ErrLatitudeRangeInvalid    = errors.New("纬度范围错误")
+	ErrLongitudeRangeInvalid   = errors.New("经度范围错误")
+)
+
+//解析坐标, 拒绝 NaN 和 Inf
+func parseCoordinate(s string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, ErrCoordinateFormatInvalid
+	}
+	return f, nil
+}
+
+// ValidateLatLng 校验经纬度, 纬度范围 [-90,90], 经度范围 [-180,180]
+// 无法解析时返回 ErrCoordinateFormatInvalid, 超出范围时返回对应的范围错误
+func ValidateLatLng(lat, lng string) (bool, error) {
+	latitude, err := parseCoordinate(lat)
+	if err != nil {
+		return false, err
+	}
+	longitude, err := parseCoordinate(lng)
+	if err != nil {
+		return false, err
+	}
+
+	if latitude < -90 || latitude > 90 {
+		return false, ErrLatitudeRangeInvalid
+	}
+	if longitude < -180 || longitude > 180 {
+		return false, ErrLongitudeRangeInvalid
+	}
+	return true, nil
+}
@@ -0,0 +1,30 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLatLng(t *testing.T) {
+	tests := []struct {
+		lat, lng string
+		err      error
+	}{
+		{"31.2304", "121.4737", nil},
+		{"-90", "180", nil},
+		{" 0 ", "-180", nil},
+		{"90.0001", "121.4737", ErrLatitudeRangeInvalid},
+		{"-91", "0", ErrLatitudeRangeInvalid},
+		{"31.2304", "180.5", ErrLongitudeRangeInvalid},
+		{"north", "121.4737", ErrCoordinateFormatInvalid},
+		{"31.2304", "", ErrCoordinateFormatInvalid},
+		{"NaN", "0", ErrCoordinateFormatInvalid},
+	}
+
+	for _, tt := range tests {
+		ok, err := ValidateLatLng(tt.lat, tt.lng)
+		assert.Equal(t, tt.err, err, tt.lat+","+tt.lng)
+		assert.Equal(t, tt.err == nil, ok, tt.lat+","+tt.lng)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -14,10 +16,14 @@ var (
 	ErrBirthRangeInvalid  = errors.New("出生日期范围错误")
 	ErrSumInvalid         = errors.New("校验和错误")
 
-	reg  = regexp.MustCompile("^(\\d{6})(18|19|20)?(\\d{2})(0\\d|10|11|12)([012]\\d|3[01])(\\d{3})(\\d|X)?$")
-	area = map[string]string{
+	reg   = regexp.MustCompile("^(\\d{6})(18|19|20)(\\d{2})(0\\d|10|11|12)([012]\\d|3[01])(\\d{3})(\\d|X)$")
+	reg15 = regexp.MustCompile("^(\\d{6})(\\d{2})(0\\d|10|11|12)([012]\\d|3[01])(\\d{3})$")
+
+	//地址码表, 可通过 RegisterArea 扩展
+	areaMu sync.RWMutex
+	area   = map[string]string{
 		"11": "北京", "12": "天津", "13": "河北", "14": "山西", "15": "内蒙",
-		"21": "辽宁", "22": "吉林", "23": "黑龙", " 31": "上海", "32": "江苏",
+		"21": "辽宁", "22": "吉林", "23": "黑龙", "31": "上海", "32": "江苏",
 		"33": "浙江", "34": "安徽", "35": "福建", "36": "江西", "37": "山东",
 		"41": "河南", "42": "湖北", "43": "湖南", "44": "广东", "45": "广西",
 		"46": "海南", "50": "重庆", "51": "四川", "52": "贵州", "53": "云南",
@@ -25,8 +31,8 @@ var (
 		"65": "新疆", "71": "台湾", "81": "香港", "82": "澳门", "91": "国外",
 	}
 
-	min_date = time.Date(1890, 0, 0, 0, 0, 0, 0, time.Local)
-	max_date = time.Now()
+	//出生日期下限, 上限为校验时的当前时间
+	min_date = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	//十七位数字本体码权重
 	weight = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
@@ -37,9 +43,62 @@ type IDCard struct {
 	Number string
 }
 
+// IDFormat 身份证号码格式
+type IDFormat int
+
+const (
+	FormatUnknown IDFormat = iota
+	// Format15 1999年以前签发的15位号码, 无世纪位和校验码
+	Format15
+	// Format18 18位号码
+	Format18
+)
+
+func (f IDFormat) String() string {
+	switch f {
+	case Format15:
+		return "15位"
+	case Format18:
+		return "18位"
+	}
+	return "未知"
+}
+
+// Gender 性别, 由顺序码的奇偶决定
+type Gender int
+
+const (
+	Female Gender = iota
+	Male
+)
+
+func (g Gender) String() string {
+	if g == Male {
+		return "男"
+	}
+	return "女"
+}
+
+//规范化号码, 兼容小写的校验码x
+func (i *IDCard) number() string {
+	return strings.ToUpper(strings.TrimSpace(i.Number))
+}
+
+//识别号码格式
+func (i *IDCard) format() IDFormat {
+	number := i.number()
+	switch {
+	case reg.MatchString(number):
+		return Format18
+	case reg15.MatchString(number):
+		return Format15
+	}
+	return FormatUnknown
+}
+
 //整体校验格式
 func (i *IDCard) validateReg() error {
-	if reg.MatchString(i.Number) {
+	if i.format() != FormatUnknown {
 		return nil
 	}
 	return ErrFormatInvalid
@@ -47,7 +106,7 @@ func (i *IDCard) validateReg() error {
 
 //校验地区码
 func (i *IDCard) validateArea() error {
-	if _, ok := area[i.Number[0:2]]; ok {
+	if _, ok := Province(i.number()); ok {
 		return nil
 	}
 
@@ -56,23 +115,48 @@ func (i *IDCard) validateArea() error {
 
 //校验生日,包括格式和范围
 func (i *IDCard) validateBirth() error {
-	birth := i.Number[6:14]
-	if date, err := time.Parse("20060102", birth); err != nil {
+	if date, err := i.birthday(); err != nil {
 		return ErrBirthFormatInvalid
-	} else if date.After(max_date) && date.Before(min_date) {
+	} else if date.After(time.Now()) || date.Before(min_date) {
 		return ErrBirthRangeInvalid
 	}
 	return nil
 }
 
-//校验和
-func (i *IDCard) validateSum() error {
+//解析出生日期, 15位号码的年份按19xx处理
+func (i *IDCard) birthday() (time.Time, error) {
+	if i.format() == Format15 {
+		return time.Parse("20060102", "19"+i.number()[6:12])
+	}
+	return time.Parse("20060102", i.number()[6:14])
+}
+
+//顺序码
+func (i *IDCard) sequence() string {
+	if i.format() == Format15 {
+		return i.number()[12:15]
+	}
+	return i.number()[14:17]
+}
+
+//根据十七位本体码计算校验码
+func checksum(body string) byte {
 	sum := 0
-	for i, char := range i.Number[:len(i.Number)-1] {
+	for i, char := range body {
 		cf, _ := strconv.ParseFloat(string(char), 64)
 		sum += int(cf) * weight[i]
 	}
-	if code[sum%11] == i.Number[len(i.Number)-1] {
+	return code[sum%11]
+}
+
+//校验和, 15位号码没有校验码
+func (i *IDCard) validateSum() error {
+	if i.format() == Format15 {
+		return nil
+	}
+
+	number := i.number()
+	if checksum(number[:17]) == number[17] {
 		return nil
 	}
 	return ErrSumInvalid
@@ -98,3 +182,93 @@ func (i *IDCard) Validate() (flag bool, err error) {
 
 	return true, nil
 }
+
+// Birthday 返回出生日期, 号码未通过校验时返回校验错误
+func (i *IDCard) Birthday() (time.Time, error) {
+	if _, err := i.Validate(); err != nil {
+		return time.Time{}, err
+	}
+	return i.birthday()
+}
+
+// Gender 返回性别, 顺序码(18位号码第15-17位, 15位号码第13-15位)末位奇数为男, 偶数为女
+func (i *IDCard) Gender() (Gender, error) {
+	if _, err := i.Validate(); err != nil {
+		return Female, err
+	}
+	if (i.sequence()[2]-'0')%2 == 1 {
+		return Male, nil
+	}
+	return Female, nil
+}
+
+// Sequence 返回顺序码(18位号码第15-17位, 15位号码第13-15位)的数值
+// 同 Gender, 奇数为男, 偶数为女
+func (i *IDCard) Sequence() (int, error) {
+	if _, err := i.Validate(); err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(i.sequence())
+}
+
+// Age 返回按当前时间计算的周岁
+func (i *IDCard) Age() (int, error) {
+	birth, err := i.Birthday()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	age := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+		age--
+	}
+	return age, nil
+}
+
+// Format 校验号码并返回识别出的格式
+func (i *IDCard) Format() (IDFormat, error) {
+	if _, err := i.Validate(); err != nil {
+		return FormatUnknown, err
+	}
+	return i.format(), nil
+}
+
+// Upgrade 将15位号码升级为18位: 插入世纪位"19"并追加校验码
+// 18位号码校验通过后原样返回
+func (i *IDCard) Upgrade() (string, error) {
+	format, err := i.Format()
+	if err != nil {
+		return "", err
+	}
+
+	number := i.number()
+	if format == Format18 {
+		return number, nil
+	}
+	body := number[:6] + "19" + number[6:]
+	return body + string(checksum(body)), nil
+}
+
+// Province 返回号码地址码对应的地区名称, 优先匹配通过 RegisterArea 注册的6位或4位地址码
+func Province(number string) (string, bool) {
+	areaMu.RLock()
+	defer areaMu.RUnlock()
+
+	for _, n := range []int{6, 4, 2} {
+		if len(number) < n {
+			continue
+		}
+		if name, ok := area[number[:n]]; ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// RegisterArea 注册或覆盖地址码, code 可为2位省级、4位市级或6位县级地址码
+func RegisterArea(code, name string) {
+	areaMu.Lock()
+	defer areaMu.Unlock()
+	area[code] = name
+}
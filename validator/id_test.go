@@ -0,0 +1,155 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDCardValidateBirth(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		err    error
+	}{
+		{"valid 1990", "110105199003071239", nil},
+		{"future", "110105209903071230", ErrBirthRangeInvalid},
+		{"before 1900", "110105188003071235", ErrBirthRangeInvalid},
+		{"bad date", "110105199002301230", ErrBirthFormatInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := IDCard{Number: tt.number}
+			ok, err := id.Validate()
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.err == nil, ok)
+		})
+	}
+}
+
+func TestIDCardDemographics(t *testing.T) {
+	male := IDCard{Number: "110105199003071239"}
+	birth, err := male.Birthday()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(1990, 3, 7, 0, 0, 0, 0, time.UTC), birth)
+
+	gender, err := male.Gender()
+	assert.NoError(t, err)
+	assert.Equal(t, Male, gender)
+
+	female := IDCard{Number: "110105199003071247"}
+	gender, err = female.Gender()
+	assert.NoError(t, err)
+	assert.Equal(t, Female, gender)
+
+	age, err := female.Age()
+	assert.NoError(t, err)
+	now := time.Now()
+	expect := now.Year() - 1990
+	if now.Month() < time.March || (now.Month() == time.March && now.Day() < 7) {
+		expect--
+	}
+	assert.Equal(t, expect, age)
+
+	for _, number := range []string{"11010519900307101X", "11010519900307101x"} {
+		lower := IDCard{Number: number}
+		gender, err = lower.Gender()
+		assert.NoError(t, err, number)
+		assert.Equal(t, Male, gender, number)
+	}
+
+	invalid := IDCard{Number: "110105199003071238"}
+	_, err = invalid.Birthday()
+	assert.Equal(t, ErrSumInvalid, err)
+	_, err = invalid.Gender()
+	assert.Equal(t, ErrSumInvalid, err)
+	_, err = invalid.Age()
+	assert.Equal(t, ErrSumInvalid, err)
+}
+
+func TestIDCardSequence(t *testing.T) {
+	for number, expect := range map[string]int{
+		"110105199003071239": 123,
+		"110105199003071247": 124,
+		"11010519900307101X": 101,
+		"110105900307123":    123,
+	} {
+		card := IDCard{Number: number}
+		seq, err := card.Sequence()
+		assert.NoError(t, err, number)
+		assert.Equal(t, expect, seq, number)
+	}
+
+	invalid := IDCard{Number: "110105199003071238"}
+	_, err := invalid.Sequence()
+	assert.Equal(t, ErrSumInvalid, err)
+}
+
+func TestIDCardLegacy(t *testing.T) {
+	legacy := IDCard{Number: "110105900307123"}
+	ok, err := legacy.Validate()
+	assert.True(t, ok)
+	assert.NoError(t, err)
+
+	format, err := legacy.Format()
+	assert.NoError(t, err)
+	assert.Equal(t, Format15, format)
+
+	upgraded, err := legacy.Upgrade()
+	assert.NoError(t, err)
+	assert.Equal(t, "110105199003071239", upgraded)
+
+	birth, err := legacy.Birthday()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(1990, 3, 7, 0, 0, 0, 0, time.UTC), birth)
+
+	gender, err := legacy.Gender()
+	assert.NoError(t, err)
+	assert.Equal(t, Male, gender)
+
+	modern := IDCard{Number: upgraded}
+	format, err = modern.Format()
+	assert.NoError(t, err)
+	assert.Equal(t, Format18, format)
+	upgraded, err = modern.Upgrade()
+	assert.NoError(t, err)
+	assert.Equal(t, modern.Number, upgraded)
+
+	for _, number := range []string{"1101059003071", "1101059003071234", "110105901307123"} {
+		invalid := IDCard{Number: number}
+		_, err = invalid.Upgrade()
+		assert.Error(t, err, number)
+	}
+}
+
+func TestIDCardArea(t *testing.T) {
+	shanghai := IDCard{Number: "310104198506123455"}
+	ok, err := shanghai.Validate()
+	assert.True(t, ok)
+	assert.NoError(t, err)
+
+	name, ok := Province(shanghai.Number)
+	assert.True(t, ok)
+	assert.Equal(t, "上海", name)
+
+	special := IDCard{Number: "990105199003071232"}
+	_, err = special.Validate()
+	assert.Equal(t, ErrAddressInvalid, err)
+	_, ok = Province(special.Number)
+	assert.False(t, ok)
+
+	RegisterArea("990105", "特区")
+	defer func() {
+		areaMu.Lock()
+		delete(area, "990105")
+		areaMu.Unlock()
+	}()
+
+	ok, err = special.Validate()
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	name, _ = Province(special.Number)
+	assert.Equal(t, "特区", name)
+}
@@ -0,0 +1,27 @@
+package validator
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrMobileInvalid = errors.New("手机号码格式错误")
+
+	mobileReg = regexp.MustCompile("^(\\+?86)?1[3-9]\\d{9}$")
+)
+
+// MobilePhone 中国大陆手机号码, 允许带 +86 或 86 前缀
+type MobilePhone struct {
+	Number string
+}
+
+// 校验
+func (m *MobilePhone) Validate() (bool, error) {
+	number := strings.Replace(strings.TrimSpace(m.Number), "-", "", -1)
+	if !mobileReg.MatchString(number) {
+		return false, ErrMobileInvalid
+	}
+	return true, nil
+}
@@ -0,0 +1,132 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrNotStruct            = errors.New("校验对象不是结构体")
+	ErrRuleInvalid          = errors.New("校验规则错误")
+	ErrPatternNotRegistered = errors.New("校验规则未注册")
+	ErrPatternMismatch      = errors.New("格式不匹配")
+
+	patternMu sync.RWMutex
+	patterns  = map[string]*regexp.Regexp{}
+)
+
+// FieldError 字段校验错误, Rule 为未通过的规则, Err 为包内定义的 Err* 错误
+type FieldError struct {
+	Field string
+	Rule  string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", e.Field, e.Err, e.Rule)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// RegisterPattern 注册命名正则规则, 供 ValidateStruct 的 `validate:"pattern:name"` 标签使用
+// 重复注册同名规则会覆盖原规则
+func RegisterPattern(name string, re *regexp.Regexp) {
+	patternMu.Lock()
+	defer patternMu.Unlock()
+	patterns[name] = re
+}
+
+func lookupPattern(name string) (*regexp.Regexp, bool) {
+	patternMu.RLock()
+	defer patternMu.RUnlock()
+	re, ok := patterns[name]
+	return re, ok
+}
+
+// ValidateStruct 按 validate 标签校验结构体字段, 多条规则以逗号分隔
+// 支持的规则:
+//   pattern:name  字段值需匹配通过 RegisterPattern 注册的 name 规则
+// 嵌套结构体会递归校验, nil 指针字段跳过. 失败时返回 *FieldError
+func ValidateStruct(s interface{}) (bool, error) {
+	v := reflect.ValueOf(s)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return false, ErrNotStruct
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return false, ErrNotStruct
+	}
+
+	if err := validateStruct(v, ""); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func validateStruct(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := prefix + field.Name
+		value := v.Field(i)
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				break
+			}
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.Ptr {
+			continue
+		}
+
+		if tag := field.Tag.Get("validate"); tag != "" {
+			if rule, err := validateField(value, tag); err != nil {
+				return &FieldError{Field: name, Rule: rule, Err: err}
+			}
+		}
+		if value.Kind() == reflect.Struct {
+			if err := validateStruct(value, name+"."); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//依次校验标签中的规则, 返回未通过的规则及错误
+func validateField(value reflect.Value, tag string) (string, error) {
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+		kind, arg := rule, ""
+		if idx := strings.Index(rule, ":"); idx >= 0 {
+			kind, arg = rule[:idx], rule[idx+1:]
+		}
+
+		switch kind {
+		case "pattern":
+			re, ok := lookupPattern(arg)
+			if !ok {
+				return rule, ErrPatternNotRegistered
+			}
+			if !re.MatchString(fmt.Sprint(value.Interface())) {
+				return rule, ErrPatternMismatch
+			}
+		case "":
+		default:
+			return rule, ErrRuleInvalid
+		}
+	}
+	return "", nil
+}
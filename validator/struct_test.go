@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type order struct {
+	SKU     string `validate:"pattern:sku"`
+	Note    string
+	Address *address
+}
+
+type address struct {
+	Zip string `validate:"pattern:zip"`
+}
+
+func TestValidateStruct(t *testing.T) {
+	RegisterPattern("sku", regexp.MustCompile(`^[A-Z]{3}-\d{4}$`))
+	RegisterPattern("zip", regexp.MustCompile(`^\d{6}$`))
+
+	ok, err := ValidateStruct(order{SKU: "ABC-1234", Address: &address{Zip: "200000"}})
+	assert.True(t, ok)
+	assert.NoError(t, err)
+
+	ok, err = ValidateStruct(&order{SKU: "ABC-1234"})
+	assert.True(t, ok)
+	assert.NoError(t, err)
+
+	ok, err = ValidateStruct(order{SKU: "abc"})
+	assert.False(t, ok)
+	assert.Equal(t, ErrPatternMismatch, err.(*FieldError).Err)
+	assert.Equal(t, "SKU", err.(*FieldError).Field)
+
+	_, err = ValidateStruct(order{SKU: "ABC-1234", Address: &address{Zip: "2000"}})
+	assert.Equal(t, "Address.Zip", err.(*FieldError).Field)
+
+	_, err = ValidateStruct(struct {
+		Code string `validate:"pattern:unknown"`
+	}{"x"})
+	assert.Equal(t, ErrPatternNotRegistered, err.(*FieldError).Err)
+	assert.Equal(t, "pattern:unknown", err.(*FieldError).Rule)
+	assert.Contains(t, err.Error(), "unknown")
+
+	_, err = ValidateStruct("not a struct")
+	assert.Equal(t, ErrNotStruct, err)
+}
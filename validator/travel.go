@@ -0,0 +1,35 @@
+package validator
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// 证件类型
+const (
+	DocIDCard   = "id_card"
+	DocPassport = "passport"
+)
+
+var (
+	ErrTravelDocInvalid = errors.New("证件号码格式错误")
+
+	//普通护照 E+8位数字 或 E+字母+7位数字, 旧版普通护照及外交、公务、因公护照 G/D/S/P+8位数字
+	passportReg = regexp.MustCompile("^(E[A-Z0-9]|[GDSP][0-9])[0-9]{7}$")
+)
+
+// ValidateTravelDoc 校验大陆身份证号码或护照号码, 返回匹配的证件类型 DocIDCard 或 DocPassport
+// 符合身份证号码格式的按身份证完整校验, 失败时返回身份证的校验错误; 两者都不匹配时返回 ErrTravelDocInvalid
+func ValidateTravelDoc(s string) (docType string, ok bool, err error) {
+	card := IDCard{Number: s}
+	if card.format() != FormatUnknown {
+		ok, err = card.Validate()
+		return DocIDCard, ok, err
+	}
+
+	if passportReg.MatchString(strings.ToUpper(strings.TrimSpace(s))) {
+		return DocPassport, true, nil
+	}
+	return "", false, ErrTravelDocInvalid
+}
@@ -0,0 +1,34 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTravelDoc(t *testing.T) {
+	tests := []struct {
+		doc     string
+		docType string
+		ok      bool
+		err     error
+	}{
+		{"110105199003071239", DocIDCard, true, nil},
+		{"110105900307123", DocIDCard, true, nil},
+		{"110105199003071238", DocIDCard, false, ErrSumInvalid},
+		{"E12345678", DocPassport, true, nil},
+		{"EA1234567", DocPassport, true, nil},
+		{" g12345678 ", DocPassport, true, nil},
+		{"P12345678", DocPassport, true, nil},
+		{"X12345678", "", false, ErrTravelDocInvalid},
+		{"E1234567", "", false, ErrTravelDocInvalid},
+		{"", "", false, ErrTravelDocInvalid},
+	}
+
+	for _, tt := range tests {
+		docType, ok, err := ValidateTravelDoc(tt.doc)
+		assert.Equal(t, tt.docType, docType, tt.doc)
+		assert.Equal(t, tt.ok, ok, tt.doc)
+		assert.Equal(t, tt.err, err, tt.doc)
+	}
+}
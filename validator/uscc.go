@@ -0,0 +1,44 @@
+package validator
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrUSCCFormatInvalid = errors.New("统一社会信用代码格式错误")
+	ErrUSCCSumInvalid    = errors.New("统一社会信用代码校验码错误")
+
+	//代码字符集, 不使用 I、O、Z、S、V
+	usccCharset = "0123456789ABCDEFGHJKLMNPQRTUWXY"
+	usccReg     = regexp.MustCompile("^[0-9A-HJ-NPQRTUWXY]{2}\\d{6}[0-9A-HJ-NPQRTUWXY]{10}$")
+	//前十七位权重
+	usccWeight = []int{1, 3, 9, 27, 19, 26, 16, 17, 20, 29, 25, 13, 8, 24, 10, 30, 28}
+)
+
+// USCC 18位统一社会信用代码(GB 32100-2015)
+type USCC struct {
+	Code string
+}
+
+//校验码: 前十七位按31进制加权求和, 31减去和模31的余数
+func (u *USCC) checksum(body string) byte {
+	sum := 0
+	for i := range body {
+		sum += strings.IndexByte(usccCharset, body[i]) * usccWeight[i]
+	}
+	return usccCharset[(31-sum%31)%31]
+}
+
+// 校验
+func (u *USCC) Validate() (bool, error) {
+	c := strings.ToUpper(strings.TrimSpace(u.Code))
+	if !usccReg.MatchString(c) {
+		return false, ErrUSCCFormatInvalid
+	}
+	if u.checksum(c[:17]) != c[17] {
+		return false, ErrUSCCSumInvalid
+	}
+	return true, nil
+}
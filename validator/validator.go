@@ -0,0 +1,13 @@
+package validator
+
+// Validator 校验器, 校验失败时返回包内定义的 Err* 错误, 便于调用方按错误类型分支处理
+type Validator interface {
+	Validate() (bool, error)
+}
+
+var (
+	_ Validator = (*IDCard)(nil)
+	_ Validator = (*MobilePhone)(nil)
+	_ Validator = (*Email)(nil)
+	_ Validator = (*USCC)(nil)
+)
@@ -0,0 +1,39 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		v   Validator
+		err error
+	}{
+		{&IDCard{Number: "110105199003071239"}, nil},
+		{&IDCard{Number: "110105199003071238"}, ErrSumInvalid},
+
+		{&MobilePhone{Number: "13812345678"}, nil},
+		{&MobilePhone{Number: "+8619912345678"}, nil},
+		{&MobilePhone{Number: "12812345678"}, ErrMobileInvalid},
+		{&MobilePhone{Number: "1381234567"}, ErrMobileInvalid},
+
+		{&Email{Address: "ops@example.com"}, nil},
+		{&Email{Address: "Ops <ops@example.com>"}, ErrEmailInvalid},
+		{&Email{Address: "ops@localhost"}, ErrEmailInvalid},
+		{&Email{Address: "ops.example.com"}, ErrEmailInvalid},
+
+		{&USCC{Code: "91350100M000100Y43"}, nil},
+		{&USCC{Code: "91110000802100433b"}, nil},
+		{&USCC{Code: "91350100M000100Y44"}, ErrUSCCSumInvalid},
+		{&USCC{Code: "91350100M000100Y4"}, ErrUSCCFormatInvalid},
+		{&USCC{Code: "9135010OM000100Y43"}, ErrUSCCFormatInvalid},
+	}
+
+	for _, tt := range tests {
+		ok, err := tt.v.Validate()
+		assert.Equal(t, tt.err, err, "%#v", tt.v)
+		assert.Equal(t, tt.err == nil, ok, "%#v", tt.v)
+	}
+}